@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"cloudsketch/internal/anonymize"
+	"cloudsketch/internal/marshall"
+	"cloudsketch/internal/snapshot"
+	"context"
+	"errors"
+	"log"
+
+	"github.com/urfave/cli/v3"
+)
+
+func newAnonymize() *cli.Command {
+	return &cli.Command{
+		Name:        "anonymize",
+		UsageText:   "cloudsketch anonymize <input snapshot> <output snapshot>",
+		Description: "Replace ids, names and IP addresses in a snapshot with consistent pseudonyms",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "mapping",
+				Usage: "write the pseudonyms and the values they replaced to this file. Keep it private",
+			},
+		},
+		Action: anonymizeSnapshot,
+	}
+}
+
+func anonymizeSnapshot(_ context.Context, command *cli.Command) error {
+	args := command.Args().Slice()
+
+	if len(args) != 2 {
+		return errors.New("command expects an input and an output file")
+	}
+
+	s, err := snapshot.Read(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	anonymizer := anonymize.New()
+
+	if err := snapshot.Write(args[1], anonymizer.Snapshot(s)); err != nil {
+		return err
+	}
+
+	log.Print(args[1])
+
+	mapping := command.String("mapping")
+
+	if mapping == "" {
+		return nil
+	}
+
+	return marshall.MarshallResources(mapping, anonymizer.Mapping())
+}
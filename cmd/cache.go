@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"cloudsketch/internal/cache"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+)
+
+func newCache() *cli.Command {
+	return &cli.Command{
+		Name:        "cache",
+		Description: "Manage cached snapshots",
+		Commands: []*cli.Command{
+			{
+				Name:        "list",
+				Aliases:     []string{"ls"},
+				Description: "List cached snapshots",
+				Action:      listCache,
+			},
+			{
+				Name:        "clean",
+				Description: "Remove cached snapshots",
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  "older-than",
+						Usage: "only remove snapshots older than this, e.g. 168h",
+					},
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "remove every cached snapshot",
+					},
+				},
+				Action: cleanCache,
+			},
+		},
+	}
+}
+
+func listCache(_ context.Context, command *cli.Command) error {
+	cacheDir, err := cacheDirectory(command)
+
+	if err != nil {
+		return err
+	}
+
+	entries, err := cache.List(cacheDir)
+
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "FILE\tPROVIDER\tFETCHED\tAGE\tVERSION\tRESOURCES")
+
+	for _, entry := range entries {
+		header := entry.Snapshot.Header
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%v\n",
+			entry.Path,
+			valueOrDash(header.Provider),
+			header.FetchedAt.Format(time.RFC3339),
+			entry.Snapshot.Age().Round(time.Second),
+			valueOrDash(header.Version),
+			len(entry.Snapshot.Resources))
+	}
+
+	return w.Flush()
+}
+
+func cleanCache(_ context.Context, command *cli.Command) error {
+	// the cache directory defaults to the working directory, never remove everything by accident
+	if command.IsSet("older-than") == command.Bool("all") {
+		return errors.New("cache clean expects either --older-than or --all")
+	}
+
+	cacheDir, err := cacheDirectory(command)
+
+	if err != nil {
+		return err
+	}
+
+	removed, err := cache.Clean(cacheDir, command.Duration("older-than"))
+
+	if err != nil {
+		return err
+	}
+
+	for _, path := range removed {
+		fmt.Printf("removed %s\n", path)
+	}
+
+	return nil
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -1,36 +1,34 @@
 package cmd
 
 import (
+	"cloudsketch/internal/anonymize"
+	"cloudsketch/internal/cache"
 	"cloudsketch/internal/config"
-	"cloudsketch/internal/datastructures/build_graph"
-	"cloudsketch/internal/frontends"
-	"cloudsketch/internal/frontends/dot"
-	"cloudsketch/internal/frontends/drawio"
-	frontendModels "cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/datastructures/set"
+	"cloudsketch/internal/filter"
 	"cloudsketch/internal/list"
-	"cloudsketch/internal/marshall"
-	"cloudsketch/internal/providers"
-	"cloudsketch/internal/providers/azure"
+	"cloudsketch/internal/providers/azure/recording"
+	"cloudsketch/pkg/sketch"
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/urfave/cli/v3"
 )
 
-var (
-	frontendmap map[string]frontends.Frontend = map[string]frontends.Frontend{
-		"drawio": drawio.New(),
-		"dot":    dot.New(),
-	}
-	providermap map[string]providers.Provider = map[string]providers.Provider{
-		"azure": azure.NewProvider(),
-	}
+const (
+	STDOUT = "-"
 )
 
-func newCloudsketch(_ context.Context, command *cli.Command) error {
+func newCloudsketch(ctx context.Context, command *cli.Command) error {
 	args := command.Args().Slice()
 
 	if len(args) == 0 {
@@ -39,162 +37,402 @@ func newCloudsketch(_ context.Context, command *cli.Command) error {
 
 	fileOrSubscriptionId := args[0]
 
-	frontendString := command.String("frontend")
+	if err := validateFrontends(command); err != nil {
+		return err
+	}
+
+	allResources, basename, err := loadResources(ctx, fileOrSubscriptionId, command)
+
+	if err != nil {
+		return err
+	}
+
+	frontendResources, err := filterResources(allResources, command)
+
+	if err != nil {
+		return err
+	}
+
+	return renderResources(ctx, allResources, frontendResources, basename, command)
+}
+
+func validateFrontends(command *cli.Command) error {
+	frontendStrings := command.StringSlice("frontend")
+
+	for _, frontendString := range frontendStrings {
+		if err := isValidInput(sketch.Frontends(), frontendString); err != nil {
+			return err
+		}
+	}
+
+	if command.String("output") != "" && len(frontendStrings) > 1 {
+		return errors.New("--output can only be used with a single frontend")
+	}
+
+	log.Printf("target frontends are %s\n", strings.Join(frontendStrings, ","))
+
+	return nil
+}
+
+// renderResources draws the resources with every requested frontend. Lint rules run on all resources, hiding a resource must
+// neither hide nor cause findings. Only the findings of drawn resources are overlaid
+func renderResources(ctx context.Context, allResources, frontendResources []*sketch.Resource, basename string, command *cli.Command) error {
+	iconBase, err := iconBase(command)
+
+	if err != nil {
+		return err
+	}
+
+	options := &sketch.RenderOptions{
+		Name:     filepath.Base(basename),
+		IconBase: iconBase,
+		Pages:    command.String("pages"),
+		Layers:   command.Bool("layers"),
+	}
+
+	if options.Layers {
+		categories, err := categories()
+
+		if err != nil {
+			return err
+		}
+
+		options.Categories = categories
+	}
+
+	if command.Bool("lint") {
+		lintOptions, err := lintOptions()
+
+		if err != nil {
+			return err
+		}
+
+		findings, err := sketch.Lint(allResources, lintOptions)
+
+		if err != nil {
+			return err
+		}
+
+		drawn := set.New[string]()
+
+		for _, resource := range frontendResources {
+			drawn.Add(resource.Id)
+		}
+
+		findings = list.Filter(findings, func(f *sketch.Finding) bool { return drawn.Contains(f.Resource.Id) })
 
-	frontend, ok := frontendmap[frontendString]
+		log.Printf("%v lint findings\n", len(findings))
 
-	if !ok {
-		return fmt.Errorf("unknown frontend %s", frontendString)
+		options.Findings = findings
 	}
 
-	providerString := command.String("provider")
+	// the same resources are rendered by every requested frontend
+	for _, frontendString := range command.StringSlice("frontend") {
+		filename := outputFilename(basename, frontendString, command.String("output"), command.String("output-dir"))
 
-	provider, ok := providermap[providerString]
+		options.Frontend = frontendString
 
-	if !ok {
-		return fmt.Errorf("unknown frontend %s", frontendString)
+		if err := writeDiagram(ctx, frontendResources, filename, options); err != nil {
+			return err
+		}
+
+		// execution succesful. Print the output file name
+		log.Print(filename)
 	}
 
-	log.Printf("target frontend is %s\n", frontendString)
-	log.Printf("target provider is %s\n", providerString)
+	return nil
+}
 
-	var resources []*providers.Resource
-	var filename string
+// loadResources returns every resource of the snapshot or subscription. Filters are applied by the callers
+func loadResources(ctx context.Context, fileOrSubscriptionId string, command *cli.Command) ([]*sketch.Resource, string, error) {
+	var s *sketch.Snapshot
+	var basename string
 
 	// command can either be a subscription id or a file name
 	if strings.HasSuffix(fileOrSubscriptionId, ".json") {
 		// if the file ends in .json, assume its a valid json file that contains previously populated Azure resources
-		existingResources, existingFilename, err := useExistingFile(fileOrSubscriptionId, frontendString)
+		existingSnapshot, existingBasename, err := useExistingFile(fileOrSubscriptionId)
 
 		if err != nil {
-			return err
+			return nil, "", err
 		}
 
-		resources = existingResources
-		filename = existingFilename
+		s = existingSnapshot
+		basename = existingBasename
 	} else {
+		log.Printf("target provider is %s\n", command.String("provider"))
+
 		// otherwise treat it as a subscription id
-		existingResources, existingFilename, err := createNewFile(fileOrSubscriptionId, frontendString, provider)
+		existingSnapshot, existingBasename, err := fetchOrUseCache(ctx, fileOrSubscriptionId, command)
 
 		if err != nil {
-			return err
+			return nil, "", err
 		}
 
-		resources = existingResources
-		filename = existingFilename
+		s = existingSnapshot
+		basename = existingBasename
+	}
+
+	if command.Bool("anonymize") {
+		anonymizer := anonymize.New()
+
+		s = anonymizer.Snapshot(s)
+		basename = filepath.Join(filepath.Dir(basename), anonymizer.Name(filepath.Base(basename)))
 	}
 
-	frontendResources, err := mapToDomainModels(resources)
+	resources, err := sketch.Resources(s)
 
 	if err != nil {
+		return nil, "", err
+	}
+
+	return resources, basename, nil
+}
+
+func outputFilename(basename, frontendString, output, outputDir string) string {
+	if output == "" {
+		output = fmt.Sprintf("%s.%s", basename, sketch.Extension(frontendString))
+
+		if outputDir != "" {
+			// the derived name is placed in the output directory regardless of where the input was read from
+			output = filepath.Base(output)
+		}
+	}
+
+	if output == STDOUT || outputDir == "" {
+		return output
+	}
+
+	return filepath.Join(outputDir, output)
+}
+
+func writeDiagram(ctx context.Context, resources []*sketch.Resource, filename string, options *sketch.RenderOptions) error {
+	if filename == STDOUT {
+		return sketch.Render(ctx, os.Stdout, resources, options)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
 		return err
 	}
 
-	frontendResources = removeBlacklistedResources(frontendResources)
+	f, err := os.Create(filename)
 
-	if err := frontend.WriteDiagram(frontendResources, filename); err != nil {
+	if err != nil {
 		return err
 	}
 
-	// execution succesful. Print the output file name
-	log.Print(filename)
+	defer f.Close()
 
-	return nil
+	return sketch.Render(ctx, f, resources, options)
 }
 
-func removeBlacklistedResources(frontendResources []*frontendModels.Resource) []*frontendModels.Resource {
-	config, ok := config.Read()
+func filterResources(frontendResources []*sketch.Resource, command *cli.Command) ([]*sketch.Resource, error) {
+	options := &sketch.FilterOptions{}
+
+	config, ok, err := config.Read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		options = &config.Filter
 
-	if !ok {
-		// no config exists. Return all resources
-		return frontendResources
+		// blacklisted types are excluded as-is
+		options.Exclude = append(options.Exclude, list.Map(config.Blacklist, func(typ string) sketch.FilterExpression {
+			return filter.Expression{Type: typ}
+		})...)
 	}
 
-	// config is present. Remove all blacklisted resources
-	toReturn := list.Filter(frontendResources, func(r *frontendModels.Resource) bool {
-		return !list.Contains(config.Blacklist, func(entry string) bool { return entry == r.Type })
-	})
+	includes, err := parseExpressions(command.StringSlice("include"))
 
-	toReturn = list.Map(toReturn, func(r *frontendModels.Resource) *frontendModels.Resource {
-		r.DependsOn = list.Filter(r.DependsOn, func(r *frontendModels.Resource) bool {
-			return !list.Contains(config.Blacklist, func(entry string) bool { return entry == r.Type })
-		})
+	if err != nil {
+		return nil, err
+	}
+
+	excludes, err := parseExpressions(command.StringSlice("exclude"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	options.Include = append(options.Include, includes...)
+	options.Exclude = append(options.Exclude, excludes...)
 
-		return r
-	})
+	if command.IsSet("hops") {
+		options.Hops = int(command.Int("hops"))
+	}
+
+	if command.IsSet("collapse") {
+		options.Collapse = command.Bool("collapse")
+	}
 
-	return toReturn
+	if command.IsSet("drop-empty") {
+		options.DropEmptyContainers = command.Bool("drop-empty")
+	}
+
+	return sketch.Filter(frontendResources, options)
 }
 
-func useExistingFile(file, frontendString string) ([]*providers.Resource, string, error) {
+func parseExpressions(expressions []string) ([]sketch.FilterExpression, error) {
+	parsed := []sketch.FilterExpression{}
+
+	for _, expression := range expressions {
+		e, err := sketch.ParseFilterExpression(expression)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, e)
+	}
+
+	return parsed, nil
+}
+
+func useExistingFile(file string) (*sketch.Snapshot, string, error) {
 	log.Printf("using existing file %s\n", file)
 
-	resources, err := marshall.UnmarshallResources[[]*providers.Resource](file)
+	s, err := sketch.Load(file)
 
 	if err != nil {
 		return nil, "", err
 	}
 
-	outFile := strings.ReplaceAll(file, ".json", fmt.Sprintf(".%s", frontendString))
-
-	return *resources, outFile, nil
+	return s, strings.TrimSuffix(file, cache.SUFFIX), nil
 }
 
-func createNewFile(subscriptionId, frontendString string, provider providers.Provider) ([]*providers.Resource, string, error) {
-	resources, filename, err := provider.FetchResources(subscriptionId)
+func fetchOrUseCache(ctx context.Context, subscriptionId string, command *cli.Command) (*sketch.Snapshot, string, error) {
+	cacheDir, err := cacheDirectory(command)
 
 	if err != nil {
 		return nil, "", err
 	}
 
-	// cache resources for next run
-	err = marshall.MarshallResources(fmt.Sprintf("%s.json", filename), resources)
+	if command.String("record") != "" && !command.Bool("record-unanonymized") {
+		return nil, "", errors.New("--record writes resource names, tags and IP addresses to the cassette. Pass --record-unanonymized to confirm")
+	}
 
-	filename = fmt.Sprintf("%s.%s", filename, frontendString)
+	if !command.Bool("refresh") && command.String("record") == "" {
+		entry, ok, err := cache.Find(cacheDir, subscriptionId)
 
-	return resources, filename, err
+		if err != nil {
+			return nil, "", err
+		}
+
+		if ok && !entry.Snapshot.IsExpired(command.Duration("cache-ttl")) {
+			log.Printf("using cached snapshot %s fetched %s ago. Use --refresh to fetch again\n", entry.Path, entry.Snapshot.Age().Round(time.Second))
+
+			return entry.Snapshot, cache.Name(entry.Path), nil
+		}
+
+		if ok {
+			log.Printf("cached snapshot %s has expired\n", entry.Path)
+		}
+	}
+
+	return createNewFile(ctx, subscriptionId, command.String("provider"), cacheDir, command.String("record"), recordingKey(command))
+}
+
+func recordingKey(command *cli.Command) []byte {
+	if command.String("record-key") != "" {
+		return []byte(command.String("record-key"))
+	}
+
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+
+	return key
 }
 
-func mapToDomainModels(resources []*providers.Resource) ([]*frontendModels.Resource, error) {
-	resource_map := &map[string]*frontendModels.Resource{}
+func createNewFile(ctx context.Context, subscriptionId, providerString, cacheDir, cassette string, key []byte) (*sketch.Snapshot, string, error) {
+	options := &sketch.FetchOptions{
+		Provider:       providerString,
+		SubscriptionId: subscriptionId,
+		Version:        version,
+	}
+
+	var recorder *recording.Recorder
+
+	if cassette != "" {
+		recorder = recording.NewRecorder(nil, key)
+
+		options.Azure = &sketch.AzureOptions{
+			ClientOptions: &arm.ClientOptions{
+				ClientOptions: policy.ClientOptions{
+					Transport: recorder,
+				},
+			},
+		}
+	}
+
+	s, err := sketch.Fetch(ctx, options)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if recorder != nil {
+		if err := recorder.Cassette().Save(cassette); err != nil {
+			return nil, "", err
+		}
+
+		log.Printf("recorded %v responses to %s. Review the cassette before committing it, resource names are not anonymized\n", len(recorder.Cassette().Interactions), cassette)
+	}
 
-	tasks := list.Map(resources, func(r *providers.Resource) *build_graph.Task {
-		return build_graph.NewTask(r.Id, r.DependsOn, []string{}, []string{}, func() { addDependenciesFromIds(r, resource_map) })
-	})
+	// cache resources for next run
+	if err := sketch.Save(cache.Path(cacheDir, s.Header.Name), s); err != nil {
+		return nil, "", err
+	}
 
-	bg, err := build_graph.NewGraph(tasks)
+	return s, s.Header.Name, nil
+}
+
+func iconBase(command *cli.Command) (string, error) {
+	if command.IsSet("icon-base") {
+		return command.String("icon-base"), nil
+	}
+
+	config, ok, err := config.Read()
 
 	if err != nil {
-		return nil, fmt.Errorf("error during construction of dependency graph: %+v", err)
+		return "", err
 	}
 
-	for _, task := range tasks {
-		bg.Resolve(task)
+	if ok {
+		return config.IconBase, nil
 	}
 
-	domainResources := []*frontendModels.Resource{}
-	for _, v := range *resource_map {
-		domainResources = append(domainResources, v)
+	// the renderer falls back to the default
+	return "", nil
+}
+
+// categories returns the categories of resource types overridden in the configuration file
+func categories() (map[string]string, error) {
+	config, ok, err := config.Read()
+
+	if err != nil || !ok {
+		return nil, err
 	}
 
-	return domainResources, nil
+	return config.Categories, nil
 }
 
-func addDependenciesFromIds(resource *providers.Resource, resource_map *map[string]*frontendModels.Resource) {
-	if (*resource_map)[resource.Id] != nil {
-		// resource already registered
-		return
+func cacheDirectory(command *cli.Command) (string, error) {
+	if command.IsSet("cache-dir") {
+		return command.String("cache-dir"), nil
 	}
 
-	dependencies := list.Map(resource.DependsOn, func(d string) *frontendModels.Resource {
-		return (*resource_map)[d]
-	})
+	config, ok, err := config.Read()
 
-	(*resource_map)[resource.Id] = &frontendModels.Resource{
-		Id:         resource.Id,
-		Type:       resource.Type,
-		Name:       resource.Name,
-		DependsOn:  dependencies,
-		Properties: resource.Properties,
+	if err != nil {
+		return "", err
+	}
+
+	if ok && config.CacheDir != "" {
+		return config.CacheDir, nil
 	}
+
+	// snapshots are stored next to the diagrams by default
+	return ".", nil
 }
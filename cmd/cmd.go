@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"cloudsketch/internal/list"
+	"cloudsketch/pkg/sketch"
 	"context"
 	"fmt"
 	"log"
@@ -19,25 +20,113 @@ func Execute() {
 		UsageText:   fmt.Sprintf("%s <subscription id>", name),
 		Description: "convert a Azure subscription to a DrawIO diagram",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
+			&cli.StringSliceFlag{
 				Name:  "frontend",
-				Usage: "visualization target",
-				Value: "drawio",
-				Validator: func(frontend string) error {
-					return isValidInput([]string{"drawio", "dot"}, frontend)
+				Usage: "visualization targets, separated by commas",
+				Value: []string{sketch.DEFAULT_FRONTEND},
+				Validator: func(frontends []string) error {
+					for _, frontend := range frontends {
+						if err := isValidInput(sketch.Frontends(), frontend); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "output file, or - for stdout. Defaults to the input name with the frontend as extension",
+			},
+			&cli.StringFlag{
+				Name:  "output-dir",
+				Usage: "directory to write output files to",
+			},
+			&cli.StringFlag{
+				Name:  "icon-base",
+				Usage: "base URL or directory of the drawio icons referenced by the d2 frontend",
+			},
+			&cli.StringFlag{
+				Name:  "pages",
+				Usage: fmt.Sprintf("split the drawio diagram into an overview and a page per %s", strings.Join(sketch.Pages(), ", ")),
+				Validator: func(pages string) error {
+					return isValidInput(sketch.Pages(), pages)
 				},
 			},
+			&cli.BoolFlag{
+				Name:  "layers",
+				Usage: "put the resources of the drawio diagram on a layer per category, which can be toggled in drawio",
+			},
 			&cli.StringFlag{
 				Name:  "provider",
 				Usage: "resource source",
-				Value: "azure",
+				Value: sketch.DEFAULT_PROVIDER,
 				Validator: func(provider string) error {
-					return isValidInput([]string{"azure"}, provider)
+					return isValidInput(sketch.Providers(), provider)
 				},
 			},
+			&cli.BoolFlag{
+				Name:  "refresh",
+				Usage: "fetch resources again even if a cached snapshot exists",
+			},
+			&cli.StringFlag{
+				Name:  "record",
+				Usage: "write the sanitized Azure responses of this run to a cassette, to replay them in provider tests. Implies --refresh",
+			},
+			&cli.BoolFlag{
+				Name:  "record-unanonymized",
+				Usage: "confirm that --record writes resource names, tags and IP addresses to the cassette. Only ids are replaced",
+			},
+			&cli.StringFlag{
+				Name:    "record-key",
+				Usage:   "key the ids in a cassette are replaced with. Recordings with the same key use the same placeholders. Defaults to a random key",
+				Sources: cli.EnvVars("CLOUDSKETCH_RECORDING_KEY"),
+			},
+			&cli.DurationFlag{
+				Name:  "cache-ttl",
+				Usage: "fetch resources again if the cached snapshot is older than this, e.g. 24h. Zero never expires",
+			},
+			&cli.StringFlag{
+				Name:    "cache-dir",
+				Usage:   "directory snapshots are cached in. Defaults to the working directory",
+				Sources: cli.EnvVars("CLOUDSKETCH_CACHE_DIR"),
+			},
+			&cli.BoolFlag{
+				Name:  "lint",
+				Usage: "highlight lint findings in the diagram. Only supported by the drawio frontend",
+			},
+			&cli.BoolFlag{
+				Name:  "anonymize",
+				Usage: "replace ids, names and IP addresses with pseudonyms in the diagram. The cached snapshot is unaffected",
+			},
+			&cli.StringSliceFlag{
+				Name:  "include",
+				Usage: "only keep resources matching <field>=<value>. Fields are type, name (regex), rg, tag and property",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: "remove resources matching <field>=<value>. Fields are type, name (regex), rg, tag and property",
+			},
+			&cli.IntFlag{
+				Name:  "hops",
+				Usage: "also keep resources this many dependencies away from included resources",
+			},
+			&cli.BoolFlag{
+				Name:  "collapse",
+				Usage: "attach resources depending on removed resources to the parent of the removed resource",
+			},
+			&cli.BoolFlag{
+				Name:  "drop-empty",
+				Usage: "remove subscriptions, virtual networks and subnets that became empty after filtering",
+			},
 		},
 		Commands: []*cli.Command{
 			newVersion(),
+			newCache(),
+			newAnonymize(),
+			newLint(),
+			newQuery(),
 		},
 		Action: newCloudsketch,
 	}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"cloudsketch/internal/config"
+	"cloudsketch/internal/lint"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/list"
+	"cloudsketch/pkg/sketch"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	// exit code used when findings at or above the --fail-on severity are reported
+	LINT_FAILURE_EXIT_CODE = 2
+)
+
+func newLint() *cli.Command {
+	return &cli.Command{
+		Name:        "lint",
+		UsageText:   "cloudsketch lint <subscription id or snapshot>",
+		Description: "Check the resources of a subscription against architecture rules",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "report format",
+				Value: lint.FORMAT_TEXT,
+				Validator: func(format string) error {
+					return isValidInput([]string{lint.FORMAT_TEXT, lint.FORMAT_JSON, lint.FORMAT_SARIF}, format)
+				},
+			},
+			&cli.StringFlag{
+				Name:  "fail-on",
+				Usage: "exit with a non-zero exit code if findings of at least this severity are reported",
+				Value: lintModels.SEVERITY_WARNING,
+				Validator: func(severity string) error {
+					return isValidInput([]string{lintModels.SEVERITY_ERROR, lintModels.SEVERITY_WARNING, lintModels.SEVERITY_NOTE, "none"}, severity)
+				},
+			},
+		},
+		Action: lintResources,
+	}
+}
+
+func lintResources(ctx context.Context, command *cli.Command) error {
+	args := command.Args().Slice()
+
+	if len(args) == 0 {
+		return errors.New("command expects one argument")
+	}
+
+	resources, _, err := loadResources(ctx, args[0], command)
+
+	if err != nil {
+		return err
+	}
+
+	options, err := lintOptions()
+
+	if err != nil {
+		return err
+	}
+
+	findings, err := sketch.Lint(resources, options)
+
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+
+	output := command.String("output")
+
+	if output != "" && output != STDOUT {
+		f, err := os.Create(output)
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		w = f
+	}
+
+	if err := lint.Write(w, command.String("format"), findings, version); err != nil {
+		return err
+	}
+
+	failOn := command.String("fail-on")
+
+	severeFindings := list.Filter(findings, func(f *lintModels.Finding) bool {
+		return lint.IsSevere(f, failOn)
+	})
+
+	if len(severeFindings) > 0 {
+		return cli.Exit(fmt.Sprintf("%v findings of severity %s or higher", len(severeFindings), failOn), LINT_FAILURE_EXIT_CODE)
+	}
+
+	return nil
+}
+
+func lintOptions() (*sketch.LintOptions, error) {
+	config, ok, err := config.Read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return &sketch.LintOptions{}, nil
+	}
+
+	return &config.Lint, nil
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"cloudsketch/internal/filter"
+	frontendModels "cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/list"
+	"cloudsketch/internal/query"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+)
+
+func newQuery() *cli.Command {
+	return &cli.Command{
+		Name:        "query",
+		UsageText:   "cloudsketch query <dependents|dependencies|path> <subscription id or snapshot> <resource> [resource]",
+		Description: "Query the dependency graph of a subscription. Resources are given by id or by name",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "render",
+				Usage: "also render the queried resource and the results with the selected frontends",
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name:        "dependents",
+				Aliases:     []string{"blast-radius"},
+				UsageText:   "cloudsketch query dependents <subscription id or snapshot> <resource>",
+				Description: "List every resource that transitively depends on the resource",
+				Action: func(ctx context.Context, command *cli.Command) error {
+					return queryReachable(ctx, command, "dependents")
+				},
+			},
+			{
+				Name:        "dependencies",
+				UsageText:   "cloudsketch query dependencies <subscription id or snapshot> <resource>",
+				Description: "List every resource the resource transitively depends on",
+				Action: func(ctx context.Context, command *cli.Command) error {
+					return queryReachable(ctx, command, "dependencies")
+				},
+			},
+			{
+				Name:        "path",
+				UsageText:   "cloudsketch query path <subscription id or snapshot> <resource> <resource>",
+				Description: "Show the shortest chain of dependencies between two resources",
+				Action:      queryPath,
+			},
+		},
+	}
+}
+
+// loadQueryResources returns every resource, the filtered resources that are queried, the basename and the remaining arguments
+func loadQueryResources(ctx context.Context, command *cli.Command, expectedArgs int) ([]*frontendModels.Resource, []*frontendModels.Resource, string, []string, error) {
+	args := command.Args().Slice()
+
+	if len(args) != expectedArgs {
+		return nil, nil, "", nil, fmt.Errorf("command expects %v arguments", expectedArgs)
+	}
+
+	if command.Bool("render") {
+		if err := validateFrontends(command); err != nil {
+			return nil, nil, "", nil, err
+		}
+	}
+
+	allResources, basename, err := loadResources(ctx, args[0], command)
+
+	if err != nil {
+		return nil, nil, "", nil, err
+	}
+
+	resources, err := filterResources(allResources, command)
+
+	if err != nil {
+		return nil, nil, "", nil, err
+	}
+
+	return allResources, resources, basename, args[1:], nil
+}
+
+func queryReachable(ctx context.Context, command *cli.Command, name string) error {
+	allResources, resources, basename, args, err := loadQueryResources(ctx, command, 2)
+
+	if err != nil {
+		return err
+	}
+
+	q, err := query.New(resources)
+
+	if err != nil {
+		return err
+	}
+
+	resource, err := q.Find(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	results := q.Dependencies(resource)
+
+	if name == "dependents" {
+		results = q.Dependents(resource)
+	}
+
+	w := tabwriter.NewWriter(queryWriter(command), 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "DISTANCE\tTYPE\tNAME\tRESOURCE GROUP")
+
+	for _, result := range results {
+		fmt.Fprintf(w, "%v\t%s\t%s\t%s\n", result.Distance, result.Resource.Type, result.Resource.Name, result.Resource.GetResourceGroup())
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(queryWriter(command), "%v %s of %s\n", len(results), name, resource.Name)
+
+	if !command.Bool("render") {
+		return nil
+	}
+
+	ids := append(list.Map(results, func(r *query.Result) string { return r.Resource.Id }), resource.Id)
+
+	return renderResources(ctx, allResources, filter.Keep(resources, ids), fmt.Sprintf("%s_%s_%s", basename, name, resource.Name), command)
+}
+
+func queryPath(ctx context.Context, command *cli.Command) error {
+	allResources, resources, basename, args, err := loadQueryResources(ctx, command, 3)
+
+	if err != nil {
+		return err
+	}
+
+	q, err := query.New(resources)
+
+	if err != nil {
+		return err
+	}
+
+	from, err := q.Find(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	to, err := q.Find(args[1])
+
+	if err != nil {
+		return err
+	}
+
+	if from.Id == to.Id {
+		return errors.New("expected two different resources")
+	}
+
+	path, ok := q.Path(from, to)
+
+	if !ok {
+		return fmt.Errorf("%s and %s do not depend on each other", from.Name, to.Name)
+	}
+
+	w := queryWriter(command)
+
+	for i, resource := range path {
+		if i > 0 {
+			fmt.Fprintln(w, "  depends on")
+		}
+
+		fmt.Fprintf(w, "%s (%s)\n", resource.Name, resource.Type)
+	}
+
+	if !command.Bool("render") {
+		return nil
+	}
+
+	ids := list.Map(path, func(r *frontendModels.Resource) string { return r.Id })
+
+	return renderResources(ctx, allResources, filter.Keep(resources, ids), fmt.Sprintf("%s_path_%s_%s", basename, from.Name, to.Name), command)
+}
+
+func queryWriter(command *cli.Command) io.Writer {
+	// a rendered diagram written to stdout must not be mixed with the query results
+	if command.Bool("render") && command.String("output") == STDOUT {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
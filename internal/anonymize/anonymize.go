@@ -0,0 +1,306 @@
+package anonymize
+
+import (
+	"cloudsketch/internal/list"
+	"cloudsketch/internal/providers"
+	"cloudsketch/internal/snapshot"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"net/netip"
+	"strings"
+	"unicode"
+)
+
+const (
+	// tags are stored as key=value
+	TAGS_PROPERTY = "tags"
+)
+
+var (
+	// properties that hold the names of other resources
+	NAME_PROPERTIES = []string{"storageAccountName"}
+
+	// properties that are dropped entirely
+	STRIPPED_PROPERTIES = []string{"link"}
+)
+
+type anonymizer struct {
+	key     []byte
+	guids   map[string]string
+	names   map[string]string
+	ips     map[string]string
+	counts  map[string]int
+	mapping map[string]string
+}
+
+func New() *anonymizer {
+	key := make([]byte, 32)
+
+	// the key only has to be unpredictable, the pseudonyms are consistent within a single run
+	_, _ = rand.Read(key)
+
+	return &anonymizer{
+		key:     key,
+		guids:   map[string]string{},
+		names:   map[string]string{},
+		ips:     map[string]string{},
+		counts:  map[string]int{},
+		mapping: map[string]string{},
+	}
+}
+
+// Mapping returns every pseudonym and the original value it replaced
+func (a *anonymizer) Mapping() map[string]string {
+	return a.mapping
+}
+
+func (a *anonymizer) Snapshot(s *snapshot.Snapshot) *snapshot.Snapshot {
+	resources := a.Resources(s.Resources)
+
+	header := *s.Header
+	header.Name = a.Name(header.Name)
+	header.TenantId = a.guid(header.TenantId)
+	header.Scopes = list.Map(header.Scopes, a.Id)
+
+	return &snapshot.Snapshot{
+		Header:    &header,
+		Resources: resources,
+	}
+}
+
+func (a *anonymizer) Resources(resources []*providers.Resource) []*providers.Resource {
+	// name resources after their type before their names are encountered as part of other ids
+	for _, resource := range resources {
+		a.name(resource.Name, strings.ToLower(strings.ReplaceAll(resource.Type, "_", "-")))
+	}
+
+	return list.Map(resources, func(r *providers.Resource) *providers.Resource {
+		return &providers.Resource{
+			Id:         a.Id(r.Id),
+			Type:       r.Type,
+			Name:       a.name(r.Name, "name"),
+			DependsOn:  list.Map(r.DependsOn, a.Id),
+			Properties: a.properties(r.Properties),
+		}
+	})
+}
+
+// Name anonymizes names of the form <subscription name>_<subscription id>
+func (a *anonymizer) Name(s string) string {
+	name, id, ok := cutLast(s, "_")
+
+	if !ok {
+		return a.name(s, "name")
+	}
+
+	return fmt.Sprintf("%s_%s", a.name(name, "name"), a.guid(id))
+}
+
+func (a *anonymizer) Id(id string) string {
+	// /subscriptions/<guid>/resourcegroups/<name>/providers/<namespace>/<type>/<name>[/<type>/<name>]
+	segments := strings.Split(id, "/")
+
+	for i := 1; i < len(segments); i++ {
+		switch strings.ToLower(segments[i-1]) {
+		case "subscriptions":
+			segments[i] = a.guid(segments[i])
+		case "resourcegroups":
+			segments[i] = a.name(segments[i], "resource-group")
+		case "providers":
+			// the namespace is followed by alternating types and names
+			for j := i + 2; j < len(segments); j += 2 {
+				segments[j] = a.name(segments[j], "name")
+			}
+
+			return strings.Join(segments, "/")
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func (a *anonymizer) properties(properties map[string][]string) map[string][]string {
+	if properties == nil {
+		return nil
+	}
+
+	result := map[string][]string{}
+
+	for k, values := range properties {
+		if list.Contains(STRIPPED_PROPERTIES, func(p string) bool { return p == k }) {
+			continue
+		}
+
+		switch {
+		case k == TAGS_PROPERTY:
+			result[k] = list.Map(values, a.tag)
+		case list.Contains(NAME_PROPERTIES, func(p string) bool { return p == k }):
+			result[k] = list.Map(values, func(v string) string { return a.name(v, "name") })
+		default:
+			result[k] = list.Map(values, a.value)
+		}
+	}
+
+	return result
+}
+
+// value pseudonymizes ids, IP addresses and DNS names. Anything else, like SKUs, kinds and access settings, does not identify a
+// subscription and is kept so the diagram and the lint rules still work on anonymized snapshots
+func (a *anonymizer) value(v string) string {
+	if strings.HasPrefix(strings.ToLower(v), "/subscriptions/") {
+		return a.Id(v)
+	}
+
+	if ip, ok := a.ipOrPrefix(v); ok {
+		return ip
+	}
+
+	if isDnsName(v) {
+		return a.name(v, "dns")
+	}
+
+	return v
+}
+
+func (a *anonymizer) tag(v string) string {
+	// keep the key so the tag can still be filtered on
+	if key, value, ok := strings.Cut(v, "="); ok {
+		return fmt.Sprintf("%s=%s", key, a.name(value, "value"))
+	}
+
+	return a.name(v, "value")
+}
+
+func isDnsName(v string) bool {
+	labels := strings.Split(strings.TrimSuffix(v, "."), ".")
+
+	if len(labels) < 2 {
+		return false
+	}
+
+	hasLetter := false
+
+	for _, label := range labels {
+		if label == "" {
+			return false
+		}
+
+		for _, c := range label {
+			isLetter := unicode.IsLetter(c)
+			hasLetter = hasLetter || isLetter
+
+			if !isLetter && !unicode.IsDigit(c) && c != '-' && c != '_' {
+				return false
+			}
+		}
+	}
+
+	// version numbers consist of digits only
+	return hasLetter
+}
+
+func (a *anonymizer) guid(guid string) string {
+	if guid == "" {
+		return guid
+	}
+
+	key := strings.ToLower(guid)
+
+	if pseudonym, ok := a.guids[key]; ok {
+		return pseudonym
+	}
+
+	pseudonym := fmt.Sprintf("00000000-0000-0000-0000-%012d", len(a.guids)+1)
+
+	a.guids[key] = pseudonym
+	a.mapping[pseudonym] = guid
+
+	return pseudonym
+}
+
+func (a *anonymizer) name(name, prefix string) string {
+	if name == "" {
+		return name
+	}
+
+	// ids are lowercase while names keep their casing. Both must map to the same pseudonym
+	key := strings.ToLower(name)
+
+	if pseudonym, ok := a.names[key]; ok {
+		return pseudonym
+	}
+
+	a.counts[prefix]++
+	pseudonym := fmt.Sprintf("%s-%v", prefix, a.counts[prefix])
+
+	a.names[key] = pseudonym
+	a.mapping[pseudonym] = name
+
+	return pseudonym
+}
+
+func (a *anonymizer) ipOrPrefix(v string) (string, bool) {
+	if pseudonym, ok := a.ips[v]; ok {
+		return pseudonym, true
+	}
+
+	var pseudonym string
+
+	if prefix, err := netip.ParsePrefix(v); err == nil {
+		// anonymize the network address and keep the prefix length so sizes and containment are preserved
+		address := a.address(prefix.Addr())
+		pseudonym = netip.PrefixFrom(address, prefix.Bits()).Masked().String()
+	} else if address, err := netip.ParseAddr(v); err == nil {
+		pseudonym = a.address(address).String()
+	} else {
+		return "", false
+	}
+
+	a.ips[v] = pseudonym
+	a.mapping[pseudonym] = v
+
+	return pseudonym, true
+}
+
+func (a *anonymizer) address(address netip.Addr) netip.Addr {
+	// prefix-preserving: every output bit is the input bit flipped by a keyed function of the preceding input bits.
+	// Two addresses sharing their first n bits therefore also share their first n bits after anonymization
+	input := address.AsSlice()
+	output := make([]byte, len(input))
+
+	for bit := range len(input) * 8 {
+		mac := hmac.New(sha256.New, a.key)
+		mac.Write([]byte{byte(bit)})
+		mac.Write(prefixBits(input, bit))
+		flip := mac.Sum(nil)[0] & 1
+
+		inputBit := (input[bit/8] >> (7 - bit%8)) & 1
+		output[bit/8] |= (inputBit ^ flip) << (7 - bit%8)
+	}
+
+	result, _ := netip.AddrFromSlice(output)
+
+	return result
+}
+
+func prefixBits(input []byte, bits int) []byte {
+	prefix := make([]byte, len(input))
+
+	for bit := range bits {
+		prefix[bit/8] |= input[bit/8] & (1 << (7 - bit%8))
+	}
+
+	return prefix
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+
+	if i < 0 {
+		return s, "", false
+	}
+
+	return s[:i], s[i+len(sep):], true
+}
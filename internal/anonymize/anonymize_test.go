@@ -0,0 +1,167 @@
+package anonymize
+
+import (
+	"cloudsketch/internal/providers"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+const (
+	SUBSCRIPTION = "/subscriptions/11111111-2222-3333-4444-555555555555"
+	VNET         = SUBSCRIPTION + "/resourceGroups/Network-RG/providers/Microsoft.Network/virtualNetworks/contoso-vnet"
+	SUBNET       = VNET + "/subnets/contoso-snet"
+	KEY_VAULT    = SUBSCRIPTION + "/resourceGroups/App-RG/providers/Microsoft.KeyVault/vaults/contoso-kv"
+	ENDPOINT     = SUBSCRIPTION + "/resourceGroups/Network-RG/providers/Microsoft.Network/privateEndpoints/contoso-kv-pe"
+)
+
+func resources() []*providers.Resource {
+	return []*providers.Resource{
+		{Id: SUBSCRIPTION, Type: "SUBSCRIPTION", Name: "Contoso Production"},
+		{Id: VNET, Type: "VIRTUAL_NETWORK", Name: "contoso-vnet", DependsOn: []string{SUBSCRIPTION}, Properties: map[string][]string{
+			"addressPrefixes": {"10.20.0.0/16"},
+			"size":            {"/16"},
+		}},
+		{Id: strings.ToLower(SUBNET), Type: "SUBNET", Name: "contoso-snet", DependsOn: []string{VNET}, Properties: map[string][]string{
+			"addressPrefixes": {"10.20.4.0/24"},
+		}},
+		{Id: KEY_VAULT, Type: "KEY_VAULT", Name: "contoso-kv", DependsOn: []string{SUBSCRIPTION}, Properties: map[string][]string{
+			"publicNetworkAccess": {"Disabled"},
+			"networkAcls":         {"Deny"},
+			"sku":                 {"Standard"},
+			"tags":                {"owner=alice@contoso.com", "environment=production"},
+			"link":                {"https://portal.azure.com/#@contoso.onmicrosoft.com/resource" + KEY_VAULT},
+		}},
+		{Id: ENDPOINT, Type: "PRIVATE_ENDPOINT", Name: "contoso-kv-pe", DependsOn: []string{strings.ToLower(SUBNET)}, Properties: map[string][]string{
+			"attachedTo": {strings.ToLower(KEY_VAULT)},
+			"ip":         {"10.20.4.5"},
+			"fqdn":       {"contoso-kv.vault.azure.net"},
+		}},
+	}
+}
+
+func byType(resources []*providers.Resource) map[string]*providers.Resource {
+	result := map[string]*providers.Resource{}
+
+	for _, resource := range resources {
+		result[resource.Type] = resource
+	}
+
+	return result
+}
+
+func TestConsistentPseudonyms(t *testing.T) {
+	anonymized := byType(New().Resources(resources()))
+
+	vnet := anonymized["VIRTUAL_NETWORK"]
+	subnet := anonymized["SUBNET"]
+	keyVault := anonymized["KEY_VAULT"]
+	endpoint := anonymized["PRIVATE_ENDPOINT"]
+
+	if subnet.DependsOn[0] != vnet.Id {
+		t.Errorf("expected the dependency of the subnet %s to be the id of the vnet %s", subnet.DependsOn[0], vnet.Id)
+	}
+
+	// ids are compared case insensitively
+	if !strings.EqualFold(endpoint.DependsOn[0], subnet.Id) {
+		t.Errorf("expected the dependency of the endpoint %s to be the id of the subnet %s", endpoint.DependsOn[0], subnet.Id)
+	}
+
+	if !strings.EqualFold(endpoint.Properties["attachedTo"][0], keyVault.Id) {
+		t.Errorf("expected the endpoint to be attached to %s, got %s", keyVault.Id, endpoint.Properties["attachedTo"][0])
+	}
+
+	if !strings.HasSuffix(keyVault.Id, "/"+keyVault.Name) {
+		t.Errorf("expected the name %s to be part of the id %s", keyVault.Name, keyVault.Id)
+	}
+}
+
+func TestIdentifyingDataIsReplaced(t *testing.T) {
+	anonymizer := New()
+	anonymized := anonymizer.Resources(resources())
+
+	for _, resource := range anonymized {
+		for _, original := range []string{"11111111-2222-3333-4444-555555555555", "contoso", "Contoso", "Network-RG", "alice", "production"} {
+			values := append([]string{resource.Id, resource.Name}, resource.DependsOn...)
+
+			for _, v := range resource.Properties {
+				values = append(values, v...)
+			}
+
+			for _, value := range values {
+				if strings.Contains(value, original) {
+					t.Errorf("%s still contains %s", value, original)
+				}
+			}
+		}
+	}
+
+	if _, ok := byType(anonymized)["KEY_VAULT"].Properties["link"]; ok {
+		t.Error("expected the portal link to be removed")
+	}
+
+	for pseudonym, original := range anonymizer.Mapping() {
+		if pseudonym == original {
+			t.Errorf("%s is mapped to itself", original)
+		}
+	}
+}
+
+func TestSettingsAreKept(t *testing.T) {
+	anonymized := byType(New().Resources(resources()))
+	keyVault := anonymized["KEY_VAULT"]
+
+	for property, expected := range map[string]string{"publicNetworkAccess": "Disabled", "networkAcls": "Deny", "sku": "Standard"} {
+		if actual := keyVault.Properties[property][0]; actual != expected {
+			t.Errorf("%s: expected %s, got %s", property, expected, actual)
+		}
+	}
+
+	if actual := anonymized["VIRTUAL_NETWORK"].Properties["size"][0]; actual != "/16" {
+		t.Errorf("expected the size to be kept, got %s", actual)
+	}
+
+	// tag keys are kept so tags can still be filtered on
+	for _, tag := range keyVault.Properties["tags"] {
+		if !strings.HasPrefix(tag, "owner=") && !strings.HasPrefix(tag, "environment=") {
+			t.Errorf("expected the tag key to be kept, got %s", tag)
+		}
+	}
+}
+
+func TestIpPrefixesArePreserved(t *testing.T) {
+	anonymized := byType(New().Resources(resources()))
+
+	vnet := netip.MustParsePrefix(anonymized["VIRTUAL_NETWORK"].Properties["addressPrefixes"][0])
+	subnet := netip.MustParsePrefix(anonymized["SUBNET"].Properties["addressPrefixes"][0])
+	ip := netip.MustParseAddr(anonymized["PRIVATE_ENDPOINT"].Properties["ip"][0])
+
+	if vnet.String() == "10.20.0.0/16" || ip.String() == "10.20.4.5" {
+		t.Errorf("expected the addresses to be replaced, got %s and %s", vnet, ip)
+	}
+
+	if vnet.Bits() != 16 || subnet.Bits() != 24 {
+		t.Errorf("expected prefix lengths 16 and 24, got %v and %v", vnet.Bits(), subnet.Bits())
+	}
+
+	if !vnet.Overlaps(subnet) || !subnet.Contains(ip) {
+		t.Errorf("expected %s to contain %s and %s to contain %s", vnet, subnet, subnet, ip)
+	}
+}
+
+func TestSameInputSameOutput(t *testing.T) {
+	anonymizer := New()
+
+	first := anonymizer.Resources(resources())
+	second := anonymizer.Resources(resources())
+
+	for i := range first {
+		if first[i].Id != second[i].Id || first[i].Name != second[i].Name {
+			t.Errorf("expected %s to be anonymized the same way twice, got %s and %s", resources()[i].Id, first[i].Id, second[i].Id)
+		}
+	}
+
+	if !strings.EqualFold(anonymizer.Id(VNET), anonymizer.Id(strings.ToLower(VNET))) {
+		t.Error("expected ids that only differ in casing to map to the same pseudonym")
+	}
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"cloudsketch/internal/list"
+	"cloudsketch/internal/snapshot"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SUFFIX = ".json"
+)
+
+type Entry struct {
+	Path     string
+	Snapshot *snapshot.Snapshot
+}
+
+func Path(dir, name string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%s", name, SUFFIX))
+}
+
+func Name(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), SUFFIX)
+}
+
+func Find(dir, subscriptionId string) (*Entry, bool, error) {
+	// snapshots are named <subscription name>_<subscription id>.json
+	entries, err := List(dir)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	entries = list.Filter(entries, func(e *Entry) bool {
+		return strings.HasSuffix(strings.ToLower(Name(e.Path)), fmt.Sprintf("_%s", strings.ToLower(subscriptionId)))
+	})
+
+	if len(entries) == 0 {
+		return nil, false, nil
+	}
+
+	// newest first
+	return entries[0], true, nil
+}
+
+func List(dir string) ([]*Entry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf("*%s", SUFFIX)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*Entry{}
+
+	for _, path := range paths {
+		// bare arrays of resources are exports the user keeps next to the cache, they are never cached or cleaned
+		s, err := snapshot.ReadEnvelope(path)
+
+		if err != nil {
+			// not every json file in the directory is a snapshot
+			continue
+		}
+
+		entries = append(entries, &Entry{
+			Path:     path,
+			Snapshot: s,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Snapshot.Header.FetchedAt.After(entries[j].Snapshot.Header.FetchedAt)
+	})
+
+	return entries, nil
+}
+
+func Clean(dir string, olderThan time.Duration) ([]string, error) {
+	entries, err := List(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+
+	for _, entry := range entries {
+		if entry.Snapshot.Age() < olderThan {
+			continue
+		}
+
+		if err := os.Remove(entry.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		removed = append(removed, entry.Path)
+	}
+
+	return removed, nil
+}
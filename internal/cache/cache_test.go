@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"cloudsketch/internal/providers"
+	"cloudsketch/internal/snapshot"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+const (
+	LEGACY_EXPORT = `[{"Id": "/subscriptions/1", "Type": "SUBSCRIPTION", "Name": "subscription"}]`
+)
+
+func write(t *testing.T, dir, name string, age time.Duration) string {
+	s := snapshot.New([]*providers.Resource{
+		{Id: "/subscriptions/1", Type: "SUBSCRIPTION", Name: "subscription"},
+	}, &providers.Metadata{Name: name}, "azure", "test")
+
+	s.Header.FetchedAt = time.Now().UTC().Add(-age)
+
+	path := Path(dir, name)
+
+	if err := snapshot.Write(path, s); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// cache directories default to the working directory, which also holds files that are not snapshots
+func populate(t *testing.T) (string, string) {
+	dir := t.TempDir()
+
+	write(t, dir, "old_1", 48*time.Hour)
+	write(t, dir, "new_2", time.Hour)
+
+	export := filepath.Join(dir, "example.json")
+
+	if err := os.WriteFile(export, []byte(LEGACY_EXPORT), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the export is old, cleaning by age must not remove it either
+	old := time.Now().Add(-72 * time.Hour)
+
+	if err := os.Chtimes(export, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "settings.json"), []byte(`{"theme": "dark"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir, export
+}
+
+func names(entries []*Entry) []string {
+	result := []string{}
+
+	for _, entry := range entries {
+		result = append(result, Name(entry.Path))
+	}
+
+	return result
+}
+
+func TestList(t *testing.T) {
+	dir, _ := populate(t)
+
+	entries, err := List(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// newest first
+	if expected := []string{"new_2", "old_1"}; !slices.Equal(names(entries), expected) {
+		t.Errorf("expected %v, got %v", expected, names(entries))
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir, _ := populate(t)
+
+	entry, ok, err := Find(dir, "1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ok || Name(entry.Path) != "old_1" {
+		t.Errorf("expected to find old_1, got %v", entry)
+	}
+
+	if _, ok, _ := Find(dir, "3"); ok {
+		t.Error("expected no snapshot for an unknown subscription")
+	}
+}
+
+func TestClean(t *testing.T) {
+	dir, export := populate(t)
+
+	removed, err := Clean(dir, 24*time.Hour)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removed) != 1 || Name(removed[0]) != "old_1" {
+		t.Errorf("expected only old_1 to be removed, got %v", removed)
+	}
+
+	for _, path := range []string{Path(dir, "new_2"), export, filepath.Join(dir, "settings.json")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to survive: %v", path, err)
+		}
+	}
+}
+
+func TestCleanAll(t *testing.T) {
+	dir, export := populate(t)
+
+	removed, err := Clean(dir, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removed) != 2 {
+		t.Errorf("expected both snapshots to be removed, got %v", removed)
+	}
+
+	if _, err := os.Stat(export); err != nil {
+		t.Errorf("expected the legacy export to survive: %v", err)
+	}
+}
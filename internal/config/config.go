@@ -1,6 +1,8 @@
 package config
 
 import (
+	"cloudsketch/internal/filter"
+	"cloudsketch/internal/lint"
 	"cloudsketch/internal/marshall"
 	"os"
 	"path"
@@ -13,13 +15,19 @@ const (
 
 type config struct {
 	Blacklist []string
+	Filter    filter.Options
+	CacheDir  string
+	Lint      lint.Options
+	IconBase  string
+	// category per resource type, for the layers of the drawio frontend
+	Categories map[string]string
 }
 
-func Read() (*config, bool) {
+func Read() (*config, bool, error) {
 	executable, err := os.Executable()
 
 	if err != nil {
-		panic(err)
+		return nil, false, err
 	}
 
 	executablePath := filepath.Dir(executable)
@@ -2,28 +2,60 @@ package build_graph
 
 import (
 	"cloudsketch/internal/list"
-	"errors"
 	"fmt"
 	"sort"
+	"strings"
 )
 
 type Build_graph struct {
 	Tasks         []*Task
 	Graph         map[string][]*Task
 	Inverse_graph map[string][]*Task
+	tasks         map[string]*Task
+	resolved      map[string]bool
+	inverse       map[string]bool
+}
+
+type CycleError struct {
+	// labels of the tasks forming the cycle. The first label is repeated at the end
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("cyclic graph detected: %s", strings.Join(e.Cycle, " -> "))
 }
 
 func NewGraph(tasks []*Task) (*Build_graph, error) {
-	graph, inverse_graph, err := buildGraph(tasks)
+	g, err := NewCyclicGraph(tasks)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cycle := findCycle(tasks, g.Graph); cycle != nil {
+		return nil, &CycleError{Cycle: cycle}
+	}
+
+	return g, nil
+}
+
+// NewCyclicGraph constructs a graph that may contain cycles. It can be queried, but tasks can only be resolved in an acyclic graph
+func NewCyclicGraph(tasks []*Task) (*Build_graph, error) {
+	taskMap, err := mapTasks(tasks)
 
 	if err != nil {
 		return nil, err
 	}
 
+	graph, inverse_graph := buildGraph(tasks, taskMap)
+
 	return &Build_graph{
 		Tasks:         tasks,
 		Graph:         graph,
 		Inverse_graph: inverse_graph,
+		tasks:         taskMap,
+		resolved:      map[string]bool{},
+		inverse:       map[string]bool{},
 	}, nil
 }
 
@@ -45,53 +77,176 @@ func NewTask(label string, references, inputs, outputs []string, action func())
 	}
 }
 
-func buildGraph(tasks []*Task) (map[string][]*Task, map[string][]*Task, error) {
-	graph := map[string][]*Task{}
-	inverse_graph := map[string][]*Task{}
+func mapTasks(tasks []*Task) (map[string]*Task, error) {
+	taskMap := make(map[string]*Task, len(tasks))
+
+	for _, task := range tasks {
+		taskMap[task.Label] = task
+	}
+
+	for _, task := range tasks {
+		for _, reference := range task.References {
+			if _, ok := taskMap[reference]; !ok {
+				return nil, fmt.Errorf("an unknown task was referenced %s", reference)
+			}
+		}
+	}
+
+	return taskMap, nil
+}
+
+func buildGraph(tasks []*Task, taskMap map[string]*Task) (map[string][]*Task, map[string][]*Task) {
+	graph := make(map[string][]*Task, len(tasks))
+	inverse_graph := make(map[string][]*Task, len(tasks))
+
+	for _, task := range tasks {
+		graph[task.Label] = []*Task{}
+		inverse_graph[task.Label] = []*Task{}
+	}
+
+	for _, task := range tasks {
+		for _, reference := range task.References {
+			dependentTask := taskMap[reference]
+
+			graph[task.Label] = append(graph[task.Label], dependentTask)
+			inverse_graph[dependentTask.Label] = append(inverse_graph[dependentTask.Label], task)
+		}
+	}
+
+	return graph, inverse_graph
+}
+
+const (
+	unvisited = iota
+	visiting
+	visited
+)
+
+// findCycle returns the first cycle found by a depth first search, or nil if the graph is acyclic
+func findCycle(tasks []*Task, graph map[string][]*Task) []string {
+	var cycle []string
+
+	depthFirst(tasks, graph, func(stack []*Task, reference *Task) bool {
+		cycle = cycleOf(stack, reference)
+
+		return false
+	})
+
+	return cycle
+}
+
+type BackEdge struct {
+	From, To string
+	// labels of the tasks forming the cycle the edge closed. The first label is repeated at the end
+	Cycle []string
+}
+
+// BreakCycles removes the references that close a cycle from the tasks, so a graph can be constructed from them.
+// Tasks are visited in label order so the same references are removed regardless of the order of the tasks
+func BreakCycles(tasks []*Task) []*BackEdge {
+	taskMap := make(map[string]*Task, len(tasks))
+
+	for _, task := range tasks {
+		taskMap[task.Label] = task
+	}
+
+	// unknown references are reported when the graph is constructed
+	graph, _ := buildGraph(tasks, taskMap)
 
-	// sort tasks lowest amount of dependencies first
-	sort.Slice(tasks, func(i, j int) bool {
-		return len(tasks[i].References) > len(tasks[j].References)
+	sorted := make([]*Task, len(tasks))
+	copy(sorted, tasks)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Label < sorted[j].Label
+	})
+
+	for label := range graph {
+		sort.Slice(graph[label], func(i, j int) bool {
+			return graph[label][i].Label < graph[label][j].Label
+		})
+	}
+
+	backEdges := []*BackEdge{}
+
+	depthFirst(sorted, graph, func(stack []*Task, reference *Task) bool {
+		backEdges = append(backEdges, &BackEdge{
+			From:  stack[len(stack)-1].Label,
+			To:    reference.Label,
+			Cycle: cycleOf(stack, reference),
+		})
+
+		return true
 	})
 
-	// if the last entry has refernces the graph is cyclic
-	if len(tasks[len(tasks)-1].References) != 0 {
-		return nil, nil, errors.New("cyclic graph detected")
+	// without the edges pointing back into the depth first search stack the graph is acyclic
+	for _, backEdge := range backEdges {
+		task := taskMap[backEdge.From]
+
+		task.References = list.Filter(task.References, func(r string) bool {
+			return r != backEdge.To
+		})
 	}
 
+	return backEdges
+}
+
+func cycleOf(stack []*Task, reference *Task) []string {
+	// the reference is on the stack. Everything from there on forms the cycle
+	i := list.FirstIndex(stack, func(t *Task) bool { return t.Label == reference.Label })
+	cycle := list.Map(stack[i:], func(t *Task) string { return t.Label })
+
+	return append(cycle, reference.Label)
+}
+
+// depthFirst calls onBackEdge for every reference to a task on the current path, until it returns false
+func depthFirst(tasks []*Task, graph map[string][]*Task, onBackEdge func(stack []*Task, reference *Task) bool) {
+	state := make(map[string]int, len(tasks))
+	next := make(map[string]int, len(tasks))
+
 	for _, task := range tasks {
-		_, ok := graph[task.Label]
-		if !ok {
-			graph[task.Label] = []*Task{}
+		if state[task.Label] != unvisited {
+			continue
 		}
 
-		for _, reference := range task.References {
-			dependentTask := list.FirstOrDefault(tasks, nil, func(t *Task) bool {
-				return t.Label == reference
-			})
+		// iterative to support long dependency chains without deep recursion
+		stack := []*Task{task}
+		state[task.Label] = visiting
+
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			references := graph[current.Label]
 
-			if dependentTask == nil {
-				return nil, nil, fmt.Errorf("an unknown task was referenced %s", reference)
+			if next[current.Label] == len(references) {
+				state[current.Label] = visited
+				stack = stack[:len(stack)-1]
+				continue
 			}
 
-			graph[task.Label] = append(graph[task.Label], dependentTask)
+			reference := references[next[current.Label]]
+			next[current.Label]++
 
-			_, ok := inverse_graph[task.Label]
-			if !ok {
-				inverse_graph[task.Label] = []*Task{}
+			switch state[reference.Label] {
+			case unvisited:
+				state[reference.Label] = visiting
+				stack = append(stack, reference)
+			case visiting:
+				if !onBackEdge(stack, reference) {
+					return
+				}
 			}
-
-			inverse_graph[dependentTask.Label] = append(inverse_graph[dependentTask.Label], task)
 		}
 	}
-
-	return graph, inverse_graph, nil
 }
 
+// ResolveInverse runs the actions of every task depending on the task, followed by the task itself. Every action runs at most once
 func (g *Build_graph) ResolveInverse(t *Task) {
-	e := g.Inverse_graph[t.Label]
+	if g.inverse[t.Label] {
+		return
+	}
 
-	for _, ref := range e {
+	g.inverse[t.Label] = true
+
+	for _, ref := range g.Inverse_graph[t.Label] {
 		// recursively resolve the tasks dependencies
 		g.ResolveInverse(ref)
 	}
@@ -100,10 +255,15 @@ func (g *Build_graph) ResolveInverse(t *Task) {
 	t.Action()
 }
 
+// Resolve runs the actions of every task the task depends on, followed by the task itself. Every action runs at most once
 func (g *Build_graph) Resolve(t *Task) {
-	e := g.Graph[t.Label]
+	if g.resolved[t.Label] {
+		return
+	}
+
+	g.resolved[t.Label] = true
 
-	for _, ref := range e {
+	for _, ref := range g.Graph[t.Label] {
 		// recursively resolve the tasks dependencies
 		g.Resolve(ref)
 	}
@@ -111,3 +271,82 @@ func (g *Build_graph) Resolve(t *Task) {
 	// when the task has no dependencies it can be resolved
 	t.Action()
 }
+
+// Dependencies returns every task the task transitively references, grouped by distance
+func (g *Build_graph) Dependencies(label string) [][]*Task {
+	return reachable(g.Graph, label)
+}
+
+// Dependents returns every task that transitively references the task, grouped by distance
+func (g *Build_graph) Dependents(label string) [][]*Task {
+	return reachable(g.Inverse_graph, label)
+}
+
+// ShortestPath returns the shortest chain of references leading from one task to another
+func (g *Build_graph) ShortestPath(from, to string) ([]*Task, bool) {
+	previous := map[string]*Task{}
+	visited := map[string]bool{from: true}
+	frontier := []string{from}
+
+	for len(frontier) > 0 {
+		next := []string{}
+
+		for _, label := range frontier {
+			for _, t := range g.Graph[label] {
+				if visited[t.Label] {
+					continue
+				}
+
+				visited[t.Label] = true
+				previous[t.Label] = g.tasks[label]
+				next = append(next, t.Label)
+
+				if t.Label != to {
+					continue
+				}
+
+				path := []*Task{t}
+
+				for p := previous[t.Label]; p != nil; p = previous[p.Label] {
+					path = append([]*Task{p}, path...)
+				}
+
+				return path, true
+			}
+		}
+
+		frontier = next
+	}
+
+	return nil, false
+}
+
+func reachable(graph map[string][]*Task, label string) [][]*Task {
+	levels := [][]*Task{}
+	visited := map[string]bool{label: true}
+	frontier := []string{label}
+
+	for len(frontier) > 0 {
+		level := []*Task{}
+
+		for _, l := range frontier {
+			for _, t := range graph[l] {
+				if visited[t.Label] {
+					continue
+				}
+
+				visited[t.Label] = true
+				level = append(level, t)
+			}
+		}
+
+		if len(level) == 0 {
+			break
+		}
+
+		levels = append(levels, level)
+		frontier = list.Map(level, func(t *Task) string { return t.Label })
+	}
+
+	return levels
+}
@@ -0,0 +1,203 @@
+package build_graph
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+const (
+	SYNTHETIC_SIZE = 20000
+)
+
+// syntheticTasks builds an acyclic graph where every task references its predecessor and the task at half its index,
+// producing long chains and many shared subtrees
+func syntheticTasks(n int, action func(label string)) []*Task {
+	tasks := make([]*Task, n)
+
+	for i := range n {
+		references := []string{}
+
+		if i > 0 {
+			references = append(references, label(i-1))
+		}
+
+		if i > 1 {
+			references = append(references, label(i/2))
+		}
+
+		l := label(i)
+		tasks[i] = NewTask(l, references, []string{}, []string{}, func() { action(l) })
+	}
+
+	return tasks
+}
+
+func label(i int) string {
+	return fmt.Sprintf("task-%v", i)
+}
+
+func TestResolveRunsEveryActionOnce(t *testing.T) {
+	counts := map[string]int{}
+
+	tasks := syntheticTasks(1000, func(label string) { counts[label]++ })
+
+	g, err := NewGraph(tasks)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, task := range tasks {
+		g.Resolve(task)
+	}
+
+	for _, task := range tasks {
+		if counts[task.Label] != 1 {
+			t.Fatalf("expected %s to run once, ran %v times", task.Label, counts[task.Label])
+		}
+	}
+}
+
+func TestResolveRunsDependenciesFirst(t *testing.T) {
+	order := []string{}
+
+	tasks := syntheticTasks(100, func(label string) { order = append(order, label) })
+
+	g, err := NewGraph(tasks)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.Resolve(tasks[len(tasks)-1])
+
+	for _, task := range tasks {
+		for _, reference := range task.References {
+			if slices.Index(order, reference) > slices.Index(order, task.Label) {
+				t.Fatalf("%s ran before its dependency %s", task.Label, reference)
+			}
+		}
+	}
+}
+
+func TestCycleIsReported(t *testing.T) {
+	tasks := []*Task{
+		NewTask("a", []string{}, []string{}, []string{}, func() {}),
+		NewTask("b", []string{"a", "d"}, []string{}, []string{}, func() {}),
+		NewTask("c", []string{"b"}, []string{}, []string{}, func() {}),
+		NewTask("d", []string{"c"}, []string{}, []string{}, func() {}),
+	}
+
+	_, err := NewGraph(tasks)
+
+	var cycleError *CycleError
+
+	if !errors.As(err, &cycleError) {
+		t.Fatalf("expected a cycle error, got %v", err)
+	}
+
+	expected := []string{"b", "d", "c", "b"}
+
+	if !slices.Equal(cycleError.Cycle, expected) {
+		t.Fatalf("expected cycle %v, got %v", expected, cycleError.Cycle)
+	}
+}
+
+func TestBreakCyclesIsIndependentOfTaskOrder(t *testing.T) {
+	newTasks := func() []*Task {
+		return []*Task{
+			NewTask("a", []string{"b"}, []string{}, []string{}, func() {}),
+			NewTask("b", []string{"c"}, []string{}, []string{}, func() {}),
+			NewTask("c", []string{"a", "c"}, []string{}, []string{}, func() {}),
+		}
+	}
+
+	tasks := newTasks()
+	reversed := newTasks()
+	slices.Reverse(reversed)
+
+	backEdges := BreakCycles(tasks)
+	reversedBackEdges := BreakCycles(reversed)
+
+	if len(backEdges) != 2 {
+		t.Fatalf("expected 2 back edges, got %v", len(backEdges))
+	}
+
+	for i := range backEdges {
+		if backEdges[i].From != reversedBackEdges[i].From || backEdges[i].To != reversedBackEdges[i].To {
+			t.Fatalf("expected the same back edges regardless of order, got %v and %v", backEdges[i], reversedBackEdges[i])
+		}
+	}
+
+	if !slices.Equal(backEdges[0].Cycle, []string{"a", "b", "c", "a"}) {
+		t.Fatalf("unexpected cycle %v", backEdges[0].Cycle)
+	}
+
+	if _, err := NewGraph(tasks); err != nil {
+		t.Fatalf("expected an acyclic graph after breaking cycles, got %v", err)
+	}
+}
+
+func TestSelfReferenceIsACycle(t *testing.T) {
+	tasks := []*Task{
+		NewTask("a", []string{"a"}, []string{}, []string{}, func() {}),
+	}
+
+	if _, err := NewGraph(tasks); err == nil {
+		t.Fatal("expected a cycle error")
+	}
+}
+
+func TestUnknownReference(t *testing.T) {
+	tasks := []*Task{
+		NewTask("a", []string{"b"}, []string{}, []string{}, func() {}),
+	}
+
+	if _, err := NewGraph(tasks); err == nil {
+		t.Fatal("expected an error for the unknown reference")
+	}
+}
+
+func BenchmarkNewGraph(b *testing.B) {
+	tasks := syntheticTasks(SYNTHETIC_SIZE, func(string) {})
+
+	for b.Loop() {
+		if _, err := NewGraph(tasks); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResolve(b *testing.B) {
+	tasks := syntheticTasks(SYNTHETIC_SIZE, func(string) {})
+
+	for b.Loop() {
+		g, err := NewGraph(tasks)
+
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for _, task := range tasks {
+			g.Resolve(task)
+		}
+	}
+}
+
+func BenchmarkResolveInverse(b *testing.B) {
+	tasks := syntheticTasks(SYNTHETIC_SIZE, func(string) {})
+
+	for b.Loop() {
+		g, err := NewGraph(tasks)
+
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for _, task := range tasks {
+			g.ResolveInverse(task)
+		}
+	}
+}
@@ -17,3 +17,11 @@ func (s *Set[T]) Add(val T) {
 func (s *Set[T]) Contains(val T) bool {
 	return s.values[val]
 }
+
+func (s *Set[T]) Remove(val T) {
+	delete(s.values, val)
+}
+
+func (s *Set[T]) Size() int {
+	return len(s.values)
+}
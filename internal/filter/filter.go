@@ -0,0 +1,322 @@
+package filter
+
+import (
+	"cloudsketch/internal/datastructures/set"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/list"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// resources that are drawn as boxes around other resources
+	CONTAINER_TYPES = []string{types.SUBSCRIPTION, types.VIRTUAL_NETWORK, types.SUBNET}
+)
+
+type Expression struct {
+	// all non-empty fields must match for the expression to match
+	Type, Name, ResourceGroup, Tag, Property string
+	// Name compiled, set when the expression is parsed or first applied
+	name *regexp.Regexp
+}
+
+type Options struct {
+	Include, Exclude    []Expression
+	Hops                int
+	Collapse            bool
+	DropEmptyContainers bool
+}
+
+func ParseExpression(s string) (Expression, error) {
+	field, value, ok := strings.Cut(s, "=")
+
+	if !ok || value == "" {
+		return Expression{}, fmt.Errorf("invalid filter expression %s. Expected <field>=<value>", s)
+	}
+
+	switch strings.ToLower(field) {
+	case "type":
+		return Expression{Type: value}, nil
+	case "name":
+		e := Expression{Name: value}
+
+		return e, e.compile()
+	case "rg", "resourcegroup":
+		return Expression{ResourceGroup: value}, nil
+	case "tag":
+		return Expression{Tag: value}, nil
+	case "property":
+		return Expression{Property: value}, nil
+	}
+
+	return Expression{}, fmt.Errorf("unknown filter field %s. Valid fields are type,name,rg,tag,property", field)
+}
+
+func (e *Expression) compile() error {
+	if e.Name == "" || e.name != nil {
+		return nil
+	}
+
+	re, err := regexp.Compile(e.Name)
+
+	if err != nil {
+		return fmt.Errorf("invalid name expression %s: %+v", e.Name, err)
+	}
+
+	e.name = re
+
+	return nil
+}
+
+// compile compiles the name of every expression, expressions from the configuration file are not parsed
+func compile(expressions []Expression) ([]Expression, error) {
+	compiled := append([]Expression{}, expressions...)
+
+	for i := range compiled {
+		if err := compiled[i].compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return compiled, nil
+}
+
+func (e *Expression) matches(resource *models.Resource) bool {
+	if e.Type != "" && e.Type != resource.Type {
+		return false
+	}
+
+	if e.name != nil && !e.name.MatchString(resource.Name) {
+		return false
+	}
+
+	if e.ResourceGroup != "" && !strings.EqualFold(e.ResourceGroup, resource.GetResourceGroup()) {
+		return false
+	}
+
+	if e.Tag != "" && !hasKeyValue(resource.Properties["tags"], e.Tag) {
+		return false
+	}
+
+	if e.Property != "" && !hasProperty(resource, e.Property) {
+		return false
+	}
+
+	return true
+}
+
+func hasKeyValue(entries []string, expression string) bool {
+	key, value, hasValue := strings.Cut(expression, "=")
+
+	return list.Contains(entries, func(entry string) bool {
+		k, v, _ := strings.Cut(entry, "=")
+
+		if !strings.EqualFold(k, key) {
+			return false
+		}
+
+		return !hasValue || v == value
+	})
+}
+
+func hasProperty(resource *models.Resource, expression string) bool {
+	key, value, hasValue := strings.Cut(expression, "=")
+
+	values, ok := resource.Properties[key]
+
+	if !ok {
+		return false
+	}
+
+	return !hasValue || list.Contains(values, func(v string) bool { return v == value })
+}
+
+func matchesAny(expressions []Expression, resource *models.Resource) bool {
+	return list.Contains(expressions, func(expression Expression) bool {
+		return expression.matches(resource)
+	})
+}
+
+func Apply(resources []*models.Resource, options *Options) ([]*models.Resource, error) {
+	include, err := compile(options.Include)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exclude, err := compile(options.Exclude)
+
+	if err != nil {
+		return nil, err
+	}
+
+	kept := set.New[string]()
+
+	if len(include) == 0 {
+		for _, resource := range resources {
+			kept.Add(resource.Id)
+		}
+	} else {
+		seeds := list.Filter(resources, func(resource *models.Resource) bool {
+			return matchesAny(include, resource)
+		})
+
+		kept = expand(seeds, resources, options.Hops)
+	}
+
+	for _, resource := range resources {
+		if matchesAny(exclude, resource) {
+			kept.Remove(resource.Id)
+		}
+	}
+
+	result := prune(resources, kept, options.Collapse)
+
+	if options.DropEmptyContainers {
+		result = dropEmptyContainers(resources, result)
+	}
+
+	return result, nil
+}
+
+// Keep removes every resource except the given ones and the dependencies pointing to removed resources
+func Keep(resources []*models.Resource, ids []string) []*models.Resource {
+	kept := set.New[string]()
+
+	for _, id := range ids {
+		kept.Add(id)
+	}
+
+	return prune(resources, kept, false)
+}
+
+func expand(seeds, resources []*models.Resource, hops int) *set.Set[string] {
+	neighbours := map[string][]*models.Resource{}
+
+	for _, resource := range resources {
+		for _, dependency := range resource.DependsOn {
+			neighbours[resource.Id] = append(neighbours[resource.Id], dependency)
+			neighbours[dependency.Id] = append(neighbours[dependency.Id], resource)
+		}
+	}
+
+	kept := set.New[string]()
+	frontier := seeds
+
+	for _, seed := range seeds {
+		kept.Add(seed.Id)
+	}
+
+	for range hops {
+		next := []*models.Resource{}
+
+		for _, resource := range frontier {
+			// every resource depends on the subscription. Walking through it would include everything
+			if resource.Type == types.SUBSCRIPTION {
+				continue
+			}
+
+			for _, neighbour := range neighbours[resource.Id] {
+				if kept.Contains(neighbour.Id) {
+					continue
+				}
+
+				kept.Add(neighbour.Id)
+				next = append(next, neighbour)
+			}
+		}
+
+		frontier = next
+	}
+
+	return kept
+}
+
+func prune(resources []*models.Resource, kept *set.Set[string], collapse bool) []*models.Resource {
+	toReturn := list.Filter(resources, func(r *models.Resource) bool {
+		return kept.Contains(r.Id)
+	})
+
+	return list.Map(toReturn, func(r *models.Resource) *models.Resource {
+		r.DependsOn = keptDependencies(r.DependsOn, kept, collapse, set.New[string]())
+		r.CyclicDependsOn = list.Filter(r.CyclicDependsOn, func(d *models.Resource) bool { return kept.Contains(d.Id) })
+
+		return r
+	})
+}
+
+func keptDependencies(dependencies []*models.Resource, kept *set.Set[string], collapse bool, seen *set.Set[string]) []*models.Resource {
+	result := []*models.Resource{}
+
+	for _, dependency := range dependencies {
+		if seen.Contains(dependency.Id) {
+			continue
+		}
+
+		seen.Add(dependency.Id)
+
+		if kept.Contains(dependency.Id) {
+			result = append(result, dependency)
+			continue
+		}
+
+		if !collapse {
+			continue
+		}
+
+		// the dependency was removed. Attach the resource to whatever the removed resource was attached to instead
+		result = append(result, keptDependencies(dependency.DependsOn, kept, collapse, seen)...)
+	}
+
+	return result
+}
+
+func dropEmptyContainers(original, resources []*models.Resource) []*models.Resource {
+	hadChildren := dependentCounts(original)
+
+	for {
+		hasChildren := dependentCounts(resources)
+
+		becameEmpty := set.New[string]()
+
+		for _, resource := range resources {
+			isContainer := list.Contains(CONTAINER_TYPES, func(t string) bool { return t == resource.Type })
+
+			if isContainer && hadChildren[resource.Id] > 0 && hasChildren[resource.Id] == 0 {
+				becameEmpty.Add(resource.Id)
+			}
+		}
+
+		if becameEmpty.Size() == 0 {
+			return resources
+		}
+
+		// removing a subnet can leave its virtual network empty, repeat until nothing changes
+		resources = list.Filter(resources, func(r *models.Resource) bool {
+			return !becameEmpty.Contains(r.Id)
+		})
+
+		for _, resource := range resources {
+			resource.DependsOn = list.Filter(resource.DependsOn, func(d *models.Resource) bool {
+				return !becameEmpty.Contains(d.Id)
+			})
+			resource.CyclicDependsOn = list.Filter(resource.CyclicDependsOn, func(d *models.Resource) bool {
+				return !becameEmpty.Contains(d.Id)
+			})
+		}
+	}
+}
+
+func dependentCounts(resources []*models.Resource) map[string]int {
+	counts := map[string]int{}
+
+	for _, resource := range resources {
+		for _, dependency := range resource.DependsOn {
+			counts[dependency.Id]++
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,184 @@
+package containment
+
+import (
+	"cloudsketch/internal/datastructures/set"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/list"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	EDGE_DEPENDENCY  = "dependency"
+	EDGE_CONTAINMENT = "containment"
+	EDGE_CYCLIC      = "cyclic"
+)
+
+var (
+	// resources that are drawn around the resources that depend on them, innermost first
+	CONTAINERS = []string{types.SUBNET, types.VIRTUAL_NETWORK, types.SUBSCRIPTION}
+
+	invalidAliasChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Tree places every resource in the innermost subnet, virtual network or subscription it depends on
+type Tree struct {
+	resources []*models.Resource
+	byId      map[string]*models.Resource
+	parents   map[string]*models.Resource
+	children  map[string][]*models.Resource
+}
+
+func New(resources []*models.Resource) *Tree {
+	t := &Tree{
+		resources: slices.SortedFunc(slices.Values(resources), func(a, b *models.Resource) int {
+			return strings.Compare(a.Id, b.Id)
+		}),
+		byId:     map[string]*models.Resource{},
+		parents:  map[string]*models.Resource{},
+		children: map[string][]*models.Resource{},
+	}
+
+	for _, resource := range t.resources {
+		t.byId[resource.Id] = resource
+	}
+
+	for _, resource := range t.resources {
+		parent := t.container(resource)
+
+		if parent == nil {
+			continue
+		}
+
+		t.parents[resource.Id] = parent
+		t.children[parent.Id] = append(t.children[parent.Id], resource)
+	}
+
+	return t
+}
+
+// Resources returns all resources sorted by id
+func (t *Tree) Resources() []*models.Resource {
+	return t.resources
+}
+
+// Roots returns the resources that are not contained in another resource
+func (t *Tree) Roots() []*models.Resource {
+	return list.Filter(t.resources, func(r *models.Resource) bool {
+		return t.parents[r.Id] == nil
+	})
+}
+
+func (t *Tree) Parent(resource *models.Resource) *models.Resource {
+	return t.parents[resource.Id]
+}
+
+func (t *Tree) Children(resource *models.Resource) []*models.Resource {
+	return t.children[resource.Id]
+}
+
+// Contains returns true if the resource is placed inside the container, directly or through other containers
+func (t *Tree) Contains(container, resource *models.Resource) bool {
+	for parent := t.parents[resource.Id]; parent != nil; parent = t.parents[parent.Id] {
+		if parent.Id == container.Id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Dependencies returns the dependencies of the resource that are not shown by containment
+func (t *Tree) Dependencies(resource *models.Resource) []*models.Resource {
+	return list.Filter(resource.DependsOn, func(dependency *models.Resource) bool {
+		_, ok := t.byId[dependency.Id]
+		return ok && !t.Contains(dependency, resource)
+	})
+}
+
+// CyclicDependencies returns the dependencies that were removed from the resource to break a cycle
+func (t *Tree) CyclicDependencies(resource *models.Resource) []*models.Resource {
+	return list.Filter(resource.CyclicDependsOn, func(dependency *models.Resource) bool {
+		_, ok := t.byId[dependency.Id]
+		return ok
+	})
+}
+
+type Edge struct {
+	Source, Target *models.Resource
+	Kind           string
+}
+
+// Edges returns every dependency between the resources, including the ones shown by containment
+func (t *Tree) Edges() []*Edge {
+	edges := []*Edge{}
+
+	for _, resource := range t.resources {
+		for _, dependency := range resource.DependsOn {
+			if _, ok := t.byId[dependency.Id]; !ok {
+				continue
+			}
+
+			kind := EDGE_DEPENDENCY
+
+			if t.Contains(dependency, resource) {
+				kind = EDGE_CONTAINMENT
+			}
+
+			edges = append(edges, &Edge{Source: resource, Target: dependency, Kind: kind})
+		}
+
+		for _, dependency := range t.CyclicDependencies(resource) {
+			edges = append(edges, &Edge{Source: resource, Target: dependency, Kind: EDGE_CYCLIC})
+		}
+	}
+
+	return edges
+}
+
+// Aliases returns a readable identifier for every resource, for formats that can not use ids as identifiers. Resources are sorted by id, so aliases are the same on every run
+func (t *Tree) Aliases() map[string]string {
+	aliases := map[string]string{}
+	used := set.New[string]()
+
+	for _, resource := range t.resources {
+		alias := Alias(fmt.Sprintf("%s_%s", resource.Type, resource.Name))
+
+		for i := 2; used.Contains(alias); i++ {
+			alias = Alias(fmt.Sprintf("%s_%s_%v", resource.Type, resource.Name, i))
+		}
+
+		used.Add(alias)
+		aliases[resource.Id] = alias
+	}
+
+	return aliases
+}
+
+// Alias turns s into an identifier that only contains lowercase letters, digits and underscores
+func Alias(s string) string {
+	return strings.Trim(invalidAliasChars.ReplaceAllString(strings.ToLower(s), "_"), "_")
+}
+
+func (t *Tree) container(resource *models.Resource) *models.Resource {
+	for _, typ := range CONTAINERS {
+		candidates := list.Filter(resource.DependsOn, func(d *models.Resource) bool {
+			_, ok := t.byId[d.Id]
+			return ok && d.Type == typ
+		})
+
+		if len(candidates) == 0 {
+			continue
+		}
+
+		// resources in several subnets are placed in the first one and point to the others
+		return slices.MinFunc(candidates, func(a, b *models.Resource) int {
+			return strings.Compare(a.Id, b.Id)
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package d2
+
+import (
+	"cloudsketch/internal/frontends/drawio/images"
+	"cloudsketch/internal/frontends/types"
+)
+
+// icons of the drawio frontend, relative to the icon base
+var icons = map[string]string{
+	types.AI_SERVICES:                           images.AI_SERVICES,
+	types.API_MANAGEMENT_API:                    images.API_MANAGEMENT_API,
+	types.API_MANAGEMENT_SERVICE:                images.API_MANAGEMENT_SERVICE,
+	types.APP_CONFIGURATION:                     images.APP_CONFIGURATION,
+	types.APP_SERVICE:                           images.APP_SERVICE,
+	types.APP_SERVICE_PLAN:                      images.APP_SERVICE_PLAN,
+	types.APPLICATION_GATEWAY:                   images.APPLICATION_GATEWAY,
+	types.APPLICATION_GROUP:                     images.APPLICATION_GROUP,
+	types.APPLICATION_INSIGHTS:                  images.APPLICATION_INSIGHTS,
+	types.APPLICATION_SECURITY_GROUP:            images.APPLICATION_SECURITY_GROUP,
+	types.BACKEND_ADDRESS_POOL:                  images.BACKEND_ADDRESS_POOL,
+	types.BASTION:                               images.BASTION,
+	types.CONNECTION:                            images.CONNECTION,
+	types.CONTAINER_APP:                         images.CONTAINER_APP,
+	types.CONTAINER_APPS_ENVIRONMENT:            images.CONTAINER_APPS_ENVIRONMENT,
+	types.CONTAINER_REGISTRY:                    images.CONTAINER_REGISTRY,
+	types.COSMOS:                                images.COSMOS,
+	types.DATA_FACTORY:                          images.DATA_FACTORY,
+	types.DATA_FACTORY_INTEGRATION_RUNTIME:      images.DATA_FACTORY_INTEGRATION_RUNTIME,
+	types.DATA_FACTORY_MANAGED_PRIVATE_ENDPOINT: images.DATA_FACTORY_MANAGED_PRIVATE_ENDPOINT,
+	types.DATABRICKS_WORKSPACE:                  images.DATABRICKS_WORKSPACE,
+	types.EXPRESS_ROUTE_CIRCUIT:                 images.EXPRESS_ROUTE_CIRCUIT,
+	types.EXPRESS_ROUTE_GATEWAY:                 images.EXPRESS_ROUTE_GATEWAY,
+	types.FUNCTION_APP:                          images.FUNCTION_APP,
+	types.HOST_POOL:                             images.HOST_POOL,
+	types.KEY_VAULT:                             images.KEY_VAULT,
+	types.LOAD_BALANCER:                         images.LOAD_BALANCER,
+	types.LOAD_BALANCER_FRONTEND:                images.LOAD_BALANCER_FRONTEND,
+	types.LOG_ANALYTICS:                         images.LOG_ANALYTICS,
+	types.LOGIC_APP:                             images.LOGIC_APP,
+	types.MACHINE_LEARNING_WORKSPACE:            images.MACHINE_LEARNING_WORKSPACE,
+	types.NAT_GATEWAY:                           images.NAT_GATEWAY,
+	types.NETWORK_INTERFACE:                     images.NETWORK_INTERFACE,
+	types.NETWORK_SECURITY_GROUP:                images.NETWORK_SECURITY_GROUP,
+	types.POSTGRES_SQL_SERVER:                   images.POSTGRES_SQL_SERVER,
+	types.PRIVATE_DNS_RESOLVER:                  images.PRIVATE_DNS_RESOLVER,
+	types.PRIVATE_DNS_ZONE:                      images.PRIVATE_DNS_ZONE,
+	types.PRIVATE_ENDPOINT:                      images.PRIVATE_ENDPOINT,
+	types.PRIVATE_LINK_SERVICE:                  images.PRIVATE_LINK_SERVICE,
+	types.PUBLIC_IP_ADDRESS:                     images.PUBLIC_IP_ADDRESS,
+	types.RECOVERY_SERVICE_VAULT:                images.RECOVERY_SERVICE_VAULT,
+	types.REDIS:                                 images.REDIS,
+	types.ROUTE_TABLE:                           images.ROUTE_TABLE,
+	types.SEARCH_SERVICE:                        images.SEARCH_SERVICE,
+	types.SIGNALR:                               images.SIGNALR,
+	types.SQL_DATABASE:                          images.SQL_DATABASE,
+	types.SQL_SERVER:                            images.SQL_SERVER,
+	types.STATIC_WEB_APP:                        images.STATIC_WEB_APP,
+	types.STORAGE_ACCOUNT:                       images.STORAGE_ACCOUNT,
+	types.SUBNET:                                images.SUBNET,
+	types.SUBSCRIPTION:                          images.SUBSCRIPTION,
+	types.USER_ASSIGNED_IDENTITY:                images.USER_ASSIGNED_IDENTITY,
+	types.VIRTUAL_HUB:                           images.VIRTUAL_HUB,
+	types.VIRTUAL_MACHINE:                       images.VIRTUAL_MACHINE,
+	types.VIRTUAL_MACHINE_SCALE_SET:             images.VIRTUAL_MACHINE_SCALE_SET,
+	types.VIRTUAL_MACHINE_SCALE_SET_INSTANCE:    images.VIRTUAL_MACHINE_SCALE_SET_INSTANCE,
+	types.VIRTUAL_NETWORK:                       images.VIRTUAL_NETWORK,
+	types.VIRTUAL_NETWORK_GATEWAY:               images.VIRTUAL_NETWORK_GATEWAY,
+	types.VIRTUAL_WAN:                           images.VIRTUAL_WAN,
+	types.WORKSPACE:                             images.WORKSPACE,
+}
@@ -0,0 +1,130 @@
+package d2
+
+import (
+	"bytes"
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/containment"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	DEFAULT_NAME = "cloudsketch"
+	INDENT       = "  "
+)
+
+var (
+	// same colors as the boxes drawn by the drawio frontend
+	containerStyles = map[string][]string{
+		types.SUBSCRIPTION:    {`stroke-dash: 3`, `fill: "#ffffff"`},
+		types.VIRTUAL_NETWORK: {`fill: "#dae8fc"`, `stroke: "#6c8ebf"`},
+		types.SUBNET:          {`fill: "#bed3f0"`, `stroke: "#6c8ebf"`},
+	}
+)
+
+type d2 struct {
+}
+
+func New() *d2 {
+	return &d2{}
+}
+
+func (d *d2) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	content := ToD2(resources, options.Name, options.IconBase)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, content)
+
+	return err
+}
+
+func ToD2(resources []*models.Resource, name, iconBase string) string {
+	if name == "" {
+		name = DEFAULT_NAME
+	}
+
+	tree := containment.New(resources)
+	aliases := tree.Aliases()
+
+	var buffer bytes.Buffer
+
+	buffer.WriteString("direction: right\n\n")
+	buffer.WriteString(fmt.Sprintf("title: %s {\n%sshape: text\n%snear: top-center\n%sstyle.font-size: 24\n}\n\n", quote(name), INDENT, INDENT, INDENT))
+
+	for _, resource := range tree.Roots() {
+		writeResource(&buffer, tree, aliases, iconBase, resource, 0)
+	}
+
+	buffer.WriteString("\n")
+
+	// edges are declared at the root, so both ends are referenced by their full path
+	for _, resource := range tree.Resources() {
+		for _, dependency := range tree.Dependencies(resource) {
+			buffer.WriteString(fmt.Sprintf("%s -> %s\n", path(tree, aliases, resource), path(tree, aliases, dependency)))
+		}
+
+		// dependencies that were removed to break a cycle
+		for _, dependency := range tree.CyclicDependencies(resource) {
+			buffer.WriteString(fmt.Sprintf("%s -> %s: {style.stroke-dash: 5}\n", path(tree, aliases, resource), path(tree, aliases, dependency)))
+		}
+	}
+
+	return buffer.String()
+}
+
+func writeResource(buffer *bytes.Buffer, tree *containment.Tree, aliases map[string]string, iconBase string, resource *models.Resource, depth int) {
+	indent := strings.Repeat(INDENT, depth)
+	children := tree.Children(resource)
+
+	buffer.WriteString(fmt.Sprintf("%s%s: %s {\n", indent, aliases[resource.Id], quote(label(resource))))
+
+	if icon, ok := icons[resource.Type]; ok && iconBase != "" {
+		buffer.WriteString(fmt.Sprintf("%s%sicon: %s\n", indent, INDENT, quote(strings.TrimSuffix(iconBase, "/")+"/"+icon)))
+	}
+
+	buffer.WriteString(fmt.Sprintf("%s%stooltip: %s\n", indent, INDENT, quote(resource.Id)))
+
+	if link := resource.GetLinkOrDefault(); link != nil {
+		buffer.WriteString(fmt.Sprintf("%s%slink: %s\n", indent, INDENT, quote(*link)))
+	}
+
+	if len(children) > 0 {
+		for _, style := range containerStyles[resource.Type] {
+			buffer.WriteString(fmt.Sprintf("%s%sstyle.%s\n", indent, INDENT, style))
+		}
+	}
+
+	for _, child := range children {
+		writeResource(buffer, tree, aliases, iconBase, child, depth+1)
+	}
+
+	buffer.WriteString(fmt.Sprintf("%s}\n", indent))
+}
+
+// path returns the key of the resource from the root, e.g. subscription.vnet.subnet.vm
+func path(tree *containment.Tree, aliases map[string]string, resource *models.Resource) string {
+	keys := []string{aliases[resource.Id]}
+
+	for parent := tree.Parent(resource); parent != nil; parent = tree.Parent(parent) {
+		keys = append([]string{aliases[parent.Id]}, keys...)
+	}
+
+	return strings.Join(keys, ".")
+}
+
+func label(resource *models.Resource) string {
+	return fmt.Sprintf("%s\n%s", resource.Name, types.Readable(resource.Type))
+}
+
+func quote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	return fmt.Sprintf(`"%s"`, replacer.Replace(s))
+}
@@ -2,14 +2,20 @@ package dot
 
 import (
 	"bytes"
-	"cloudsketch/internal/datastructures/build_graph"
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/containment"
 	"cloudsketch/internal/frontends/models"
-	"cloudsketch/internal/list"
+	"cloudsketch/internal/frontends/types"
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 )
 
+const (
+	DEFAULT_NAME = "cloudsketch"
+)
+
 type dot struct {
 }
 
@@ -17,108 +23,100 @@ func New() *dot {
 	return &dot{}
 }
 
-func removeChars(s string) string {
-	// dot format does not allow certain characters
-	r := []string{"-", "_", "/", "."}
+func (d *dot) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	content := ToDotFile(resources, options.Name)
 
-	for _, c := range r {
-		s = strings.ReplaceAll(s, c, "")
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	return s
-}
-
-func (d *dot) WriteDiagram(resources []*models.Resource, filename string) error {
-	tasks := list.Map(resources, func(r *models.Resource) *build_graph.Task {
-		return build_graph.NewTask(r.Name, list.Map(r.DependsOn, func(r *models.Resource) string { return r.Name }), []string{}, []string{}, func() {})
-	})
-
-	tasks = list.Map(tasks, func(task *build_graph.Task) *build_graph.Task {
-		return &build_graph.Task{
-			Label:      removeChars(task.Label),
-			References: list.Map(task.References, removeChars),
-			Inputs:     list.Map(task.Inputs, removeChars),
-			Outputs:    list.Map(task.Outputs, removeChars),
-		}
-	})
+	_, err := io.WriteString(w, content)
 
-	bg, err := build_graph.NewGraph(tasks)
+	return err
+}
 
-	if err != nil {
-		return err
+func ToDotFile(resources []*models.Resource, name string) string {
+	if name == "" {
+		name = DEFAULT_NAME
 	}
 
-	graphName := graphName(filename)
-
-	// directory
-	if strings.Contains("/", graphName) {
-		s := strings.Split(graphName, "/")
-		graphName = s[len(s)-1]
-	}
+	tree := containment.New(resources)
 
-	content := ToDotFile(bg, graphName)
+	var buffer bytes.Buffer
 
-	f, err := os.Create(filename)
+	buffer.WriteString(fmt.Sprintf("digraph %s {\n", quote(name)))
+	buffer.WriteString(fmt.Sprintf("\tgraph [label=%s labelloc=t rankdir=LR fontname=Helvetica];\n", quote(name)))
+	buffer.WriteString("\tnode [style=\"filled,rounded\" fontname=Helvetica fontsize=10];\n")
+	buffer.WriteString("\tedge [fontname=Helvetica fontsize=8];\n")
 
-	if err != nil {
-		return err
+	for _, resource := range tree.Roots() {
+		writeResource(&buffer, tree, resource, 1)
 	}
 
-	defer f.Close()
+	for _, resource := range tree.Resources() {
+		writeEdges(&buffer, tree, resource)
+	}
 
-	_, err = f.WriteString(content)
+	buffer.WriteString("}\n")
 
-	return err
+	return buffer.String()
 }
 
-func graphName(filename string) string {
-	graphName := removeChars(strings.ReplaceAll(filename, ".dot", ""))
+func writeResource(buffer *bytes.Buffer, tree *containment.Tree, resource *models.Resource, depth int) {
+	indent := strings.Repeat("\t", depth)
+	children := tree.Children(resource)
 
-	// directory
-	if strings.Contains("/", graphName) {
-		s := strings.Split(graphName, "/")
-		graphName = s[len(s)-1]
+	if len(children) == 0 {
+		buffer.WriteString(fmt.Sprintf("%s%s;\n", indent, nodeStatement(resource)))
+		return
 	}
 
-	return graphName
-}
+	style := clusterStyles[resource.Type]
 
-func ToDotFile(g *build_graph.Build_graph, name string) string {
-	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%ssubgraph %s {\n", indent, quote("cluster_"+resource.Id)))
+	buffer.WriteString(fmt.Sprintf("%s\tgraph [label=%s style=%s fillcolor=%s color=%s];\n", indent, quote(resource.Name), quote(style.style), quote(style.fillColor), quote(style.color)))
+	buffer.WriteString(fmt.Sprintf("%s\t%s;\n", indent, nodeStatement(resource)))
+
+	for _, child := range children {
+		writeResource(buffer, tree, child, depth+1)
+	}
 
-	buffer.WriteString(fmt.Sprintf("digraph %s {\n", name))
+	buffer.WriteString(fmt.Sprintf("%s}\n", indent))
+}
 
-	for _, task := range g.Tasks {
-		writeInputNodes(&buffer, task.Label, task.Inputs)
+func writeEdges(buffer *bytes.Buffer, tree *containment.Tree, resource *models.Resource) {
+	for _, dependency := range tree.Dependencies(resource) {
+		kind := EDGE_DEPENDENCY
 
-		writeReferences(&buffer, task.Label, task.References)
+		if types.Category(dependency.Type) == types.CATEGORY_NETWORK {
+			kind = EDGE_NETWORK
+		}
 
-		writeOutputNodes(&buffer, task.Label, task.Outputs)
+		writeEdge(buffer, resource, dependency, kind)
 	}
 
-	buffer.WriteString("}")
+	for _, dependency := range tree.CyclicDependencies(resource) {
+		writeEdge(buffer, resource, dependency, EDGE_CYCLIC)
+	}
+}
 
-	return buffer.String()
+func writeEdge(buffer *bytes.Buffer, source, target *models.Resource, kind string) {
+	buffer.WriteString(fmt.Sprintf("\t%s -> %s [%s];\n", quote(source.Id), quote(target.Id), edgeStyles[kind]))
 }
 
-func writeInputNodes(buffer *bytes.Buffer, label string, inputs []string) {
-	for _, input := range inputs {
-		buffer.WriteString("\t")
-		buffer.WriteString(fmt.Sprintf(`%s [label="%s" shape=plaintext];`, input, input))
-		buffer.WriteString(fmt.Sprintf("\n\t%s -> %s;\n", input, label))
-	}
+func nodeStatement(resource *models.Resource) string {
+	style := styleOf(resource.Type)
+
+	return fmt.Sprintf("%s [label=%s shape=%s fillcolor=%s color=%s]", quote(resource.Id), quote(label(resource)), style.shape, quote(style.fillColor), quote(style.color))
 }
 
-func writeReferences(buffer *bytes.Buffer, label string, references []string) {
-	for _, reference := range references {
-		buffer.WriteString(fmt.Sprintf("\t%s -> %s;\n", label, reference))
-	}
+func label(resource *models.Resource) string {
+	return fmt.Sprintf("%s\n%s", resource.Name, types.Readable(resource.Type))
 }
 
-func writeOutputNodes(buffer *bytes.Buffer, label string, outputs []string) {
-	for _, output := range outputs {
-		buffer.WriteString("\t")
-		buffer.WriteString(fmt.Sprintf(`%s [label="%s" shape=plaintext];`, output, output))
-		buffer.WriteString(fmt.Sprintf("\n\t%s -> %s;\n", label, output))
-	}
+// quote returns s as a quoted dot id
+func quote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	return fmt.Sprintf(`"%s"`, replacer.Replace(s))
 }
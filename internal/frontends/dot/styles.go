@@ -0,0 +1,55 @@
+package dot
+
+import "cloudsketch/internal/frontends/types"
+
+const (
+	EDGE_DEPENDENCY = "dependency"
+	EDGE_NETWORK    = "network"
+	EDGE_CYCLIC     = "cyclic"
+)
+
+type nodeStyle struct {
+	shape, fillColor, color string
+}
+
+type clusterStyle struct {
+	style, fillColor, color string
+}
+
+var (
+	defaultNodeStyle = nodeStyle{shape: "box", fillColor: "#ffffff", color: "#666666"}
+
+	nodeStyles = map[string]nodeStyle{
+		types.CATEGORY_NETWORK:     {shape: "box", fillColor: "#dae8fc", color: "#6c8ebf"},
+		types.CATEGORY_COMPUTE:     {shape: "component", fillColor: "#ffe6cc", color: "#d79b00"},
+		types.CATEGORY_DATA:        {shape: "cylinder", fillColor: "#d5e8d4", color: "#82b366"},
+		types.CATEGORY_SECURITY:    {shape: "octagon", fillColor: "#fff2cc", color: "#d6b656"},
+		types.CATEGORY_MONITORING:  {shape: "note", fillColor: "#e1d5e7", color: "#9673a6"},
+		types.CATEGORY_INTEGRATION: {shape: "hexagon", fillColor: "#f8cecc", color: "#b85450"},
+		types.CATEGORY_SCOPE:       {shape: "folder", fillColor: "#f5f5f5", color: "#666666"},
+	}
+
+	// same colors as the boxes drawn by the drawio frontend
+	clusterStyles = map[string]clusterStyle{
+		types.SUBSCRIPTION:    {style: "dashed", fillColor: "#ffffff", color: "#666666"},
+		types.VIRTUAL_NETWORK: {style: "filled,rounded", fillColor: "#dae8fc", color: "#6c8ebf"},
+		types.SUBNET:          {style: "filled,rounded", fillColor: "#bed3f0", color: "#6c8ebf"},
+	}
+
+	edgeStyles = map[string]string{
+		EDGE_DEPENDENCY: `color="#666666"`,
+		EDGE_NETWORK:    `color="#6c8ebf"`,
+		// dependencies that were removed to break a cycle should not affect the layout
+		EDGE_CYCLIC: `color="#b85450" style=dashed constraint=false`,
+	}
+)
+
+func styleOf(typ string) nodeStyle {
+	style, ok := nodeStyles[types.Category(typ)]
+
+	if !ok {
+		return defaultNodeStyle
+	}
+
+	return style
+}
@@ -0,0 +1,162 @@
+package drawio
+
+import (
+	"cloudsketch/internal/frontends/drawio/handlers/node"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
+	lintModels "cloudsketch/internal/lint/models"
+	"fmt"
+	"html"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	BADGE_WIDTH  = 40
+	BADGE_HEIGHT = 36
+)
+
+var (
+	SEVERITY_COLORS = map[string]string{
+		lintModels.SEVERITY_ERROR:   "#FF0000",
+		lintModels.SEVERITY_WARNING: "#FF8000",
+		lintModels.SEVERITY_NOTE:    "#3399FF",
+	}
+
+	// these resources are drawn as boxes. Instead of a badge the box itself is highlighted
+	BOXED_RESOURCES = []string{types.SUBNET, types.VIRTUAL_NETWORK}
+)
+
+func findingsByResource(findings []*lintModels.Finding, resource_map *map[string]*node.ResourceAndNode) map[string][]*lintModels.Finding {
+	result := map[string][]*lintModels.Finding{}
+
+	for _, finding := range findings {
+		if _, ok := (*resource_map)[finding.Resource.Id]; !ok {
+			// resource was not drawn
+			continue
+		}
+
+		result[finding.Resource.Id] = append(result[finding.Resource.Id], finding)
+	}
+
+	return result
+}
+
+// addFindingBadges attaches a badge to every resource with findings. It must run before resources are moved into boxes.
+// It returns the groups that were created and the badges
+func addFindingBadges(ids *guid.Sequence, findings map[string][]*lintModels.Finding, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, []*node.Node, error) {
+	groups := []*node.Node{}
+	badges := []*node.Node{}
+
+	for _, resourceId := range slices.Sorted(maps.Keys(findings)) {
+		resourceFindings := findings[resourceId]
+		resourceAndNode := (*resource_map)[resourceId]
+
+		resourceAndNode.Node.SetProperty("tooltip", tooltip(resourceFindings))
+
+		if isBoxedResource(resourceAndNode.Resource.Type) {
+			continue
+		}
+
+		badge := newBadge(ids, resourceFindings)
+
+		if resourceAndNode.Node.ContainedIn == nil {
+			group, err := node.GroupIconsAndSetPosition(ids, resourceAndNode.Node, badge, node.BOTTOM_RIGHT)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			groups = append(groups, group)
+		} else {
+			// the resource is already grouped with other icons, add the badge to the existing group
+			group := resourceAndNode.Node.ContainedIn
+			badgeGeometry := badge.GetGeometry()
+
+			badge.SetProperty("parent", group.Id())
+			badge.SetDimensions(badgeGeometry.Width/2, badgeGeometry.Height/2)
+			badge.ContainedIn = group
+
+			if err := node.SetIconRelativeTo(badge, resourceAndNode.Node, node.BOTTOM_RIGHT); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		// grouping clears the label of the corner icon
+		badge.SetProperty("value", "!")
+		badge.SetProperty("tooltip", tooltip(resourceFindings))
+
+		badges = append(badges, badge)
+	}
+
+	return groups, badges, nil
+}
+
+// highlightBoxes draws the box around subnets and virtual networks with findings in the color of the most severe finding
+func highlightBoxes(findings map[string][]*lintModels.Finding, resource_map *map[string]*node.ResourceAndNode) {
+	for _, resourceId := range slices.Sorted(maps.Keys(findings)) {
+		resourceFindings := findings[resourceId]
+		resourceAndNode := (*resource_map)[resourceId]
+
+		if !isBoxedResource(resourceAndNode.Resource.Type) {
+			continue
+		}
+
+		color := SEVERITY_COLORS[mostSevere(resourceFindings)]
+
+		if resourceAndNode.Node.Box == nil {
+			// empty subnets and virtual networks are drawn without a box
+			resourceAndNode.Node.AddStyle(fmt.Sprintf("imageBorder=%s;strokeWidth=3", color))
+			continue
+		}
+
+		resourceAndNode.Node.Box.AddStyle(fmt.Sprintf("strokeColor=%s;strokeWidth=3", color))
+	}
+}
+
+func newBadge(ids *guid.Sequence, findings []*lintModels.Finding) *node.Node {
+	color := SEVERITY_COLORS[mostSevere(findings)]
+
+	return node.NewGeneric(ids, map[string]any{
+		"style": fmt.Sprintf("triangle;direction=north;whiteSpace=wrap;html=1;fontStyle=1;fontColor=#FFFFFF;verticalAlign=bottom;fillColor=%s;strokeColor=%s;", color, color),
+		"value": "!",
+	}, &node.Geometry{
+		X:      0,
+		Y:      0,
+		Width:  BADGE_WIDTH,
+		Height: BADGE_HEIGHT,
+	})
+}
+
+func tooltip(findings []*lintModels.Finding) string {
+	lines := []string{}
+
+	for _, finding := range findings {
+		lines = append(lines, html.EscapeString(fmt.Sprintf("[%s] %s: %s", finding.Severity, finding.RuleId, finding.Message)))
+	}
+
+	return strings.Join(lines, "&#10;")
+}
+
+func mostSevere(findings []*lintModels.Finding) string {
+	for _, severity := range []string{lintModels.SEVERITY_ERROR, lintModels.SEVERITY_WARNING} {
+		for _, finding := range findings {
+			if finding.Severity == severity {
+				return severity
+			}
+		}
+	}
+
+	return lintModels.SEVERITY_NOTE
+}
+
+func isBoxedResource(typ string) bool {
+	for _, boxed := range BOXED_RESOURCES {
+		if typ == boxed {
+			return true
+		}
+	}
+
+	return false
+}
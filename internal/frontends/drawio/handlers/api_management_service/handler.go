@@ -5,6 +5,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 )
 
 type handler struct{}
@@ -20,7 +21,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -30,27 +31,31 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 
 	link := resource.GetLinkOrDefault()
 
-	return node.NewIcon(IMAGE, resource.Name, &geometry, link)
+	return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
 }
 
-func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
-	return nil
+func (*handler) PostProcessIcon(ids *guid.Sequence, resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
+	return nil, nil
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	return node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{})
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	return node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{})
 }
 
-func (*handler) GroupResources(resource *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
+func (*handler) GroupResources(ids *guid.Sequence, resource *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
 	resourcesInAPIM := node.GetChildResourcesOfType(resources, resource.Id, types.API_MANAGEMENT_API, resource_map)
 
 	if len(resourcesInAPIM) == 0 {
-		return []*node.Node{}
+		return []*node.Node{}, nil
 	}
 
 	apimNode := (*resource_map)[resource.Id].Node
 
-	box := node.BoxResources(apimNode, resourcesInAPIM)
+	box, err := node.BoxResources(ids, apimNode, resourcesInAPIM)
 
-	return []*node.Node{box}
+	if err != nil {
+		return nil, err
+	}
+
+	return []*node.Node{box}, nil
 }
@@ -6,6 +6,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 	"cloudsketch/internal/list"
 )
 
@@ -26,7 +27,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -36,22 +37,22 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 
 	link := resource.GetLinkOrDefault()
 
-	return node.NewIcon(IMAGE, resource.Name, &geometry, link)
+	return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
 }
 
-func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
-	return nil
+func (*handler) PostProcessIcon(ids *guid.Sequence, resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
+	return nil, nil
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	return node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{})
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	return node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{})
 }
 
-func (*handler) GroupResources(containerEnvironment *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
+func (*handler) GroupResources(ids *guid.Sequence, containerEnvironment *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
 	resourcesInContainerEnvironment := node.GetChildResourcesOfType(resources, containerEnvironment.Id, types.CONTAINER_APP, resource_map)
 
 	if len(resourcesInContainerEnvironment) == 0 {
-		return []*node.Node{}
+		return []*node.Node{}, nil
 	}
 
 	seenGroups := set.New[string]()
@@ -70,7 +71,11 @@ func (*handler) GroupResources(containerEnvironment *models.Resource, resources
 
 	containerEnvironmentNode := (*resource_map)[containerEnvironment.Id].Node
 
-	box := node.BoxResources(containerEnvironmentNode, resourcesInContainerEnvironment)
+	box, err := node.BoxResources(ids, containerEnvironmentNode, resourcesInContainerEnvironment)
 
-	return []*node.Node{box}
+	if err != nil {
+		return nil, err
+	}
+
+	return []*node.Node{box}, nil
 }
@@ -6,6 +6,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 	"cloudsketch/internal/list"
 )
 
@@ -26,7 +27,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -36,19 +37,18 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 
 	link := resource.GetLinkOrDefault()
 
-	return node.NewIcon(IMAGE, resource.Name, &geometry, link)
+	return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
 }
 
-func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
-	return nil
-
+func (*handler) PostProcessIcon(ids *guid.Sequence, resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
+	return nil, nil
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	return node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{})
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	return node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{})
 }
 
-func (*handler) GroupResources(dataFactory *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
+func (*handler) GroupResources(ids *guid.Sequence, dataFactory *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
 	resourcesInDataFactory := node.GetChildResources(resources, dataFactory.Id, resource_map)
 
 	// the resouces in the data factory can include its private endpoint, this needs to be handled differently
@@ -70,7 +70,7 @@ func (*handler) GroupResources(dataFactory *models.Resource, resources []*models
 	dataFactoryGroup := dataFactoryNode.GetParentOrThis()
 	dataFactoryGroupGeometry := dataFactoryGroup.GetGeometry()
 
-	box := node.NewBox(&node.Geometry{
+	box := node.NewBox(ids, &node.Geometry{
 		X:      dataFactoryGroupGeometry.X,
 		Y:      dataFactoryGroupGeometry.Y,
 		Width:  0,
@@ -82,7 +82,10 @@ func (*handler) GroupResources(dataFactory *models.Resource, resources []*models
 
 		for _, attachedResource := range attachedResources {
 			attachedResource.Node.SetDimensions(attachedResource.Node.GetGeometry().Width/2, attachedResource.Node.GetGeometry().Height/2)
-			node.SetIconRelativeTo(attachedResource.Node, dataFactoryNode, node.TOP_RIGHT)
+
+			if err := node.SetIconRelativeTo(attachedResource.Node, dataFactoryNode, node.TOP_RIGHT); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -90,6 +93,7 @@ func (*handler) GroupResources(dataFactory *models.Resource, resources []*models
 
 	dataFactoryGroup.SetProperty("parent", box.Id())
 	dataFactoryGroup.ContainedIn = box
+	dataFactoryNode.Box = box
 	dataFactoryGroup.SetPosition(0, 0)
 
 	nodesToMove := list.Map(resourcesInDataFactory, func(r *node.ResourceAndNode) *node.Node {
@@ -98,7 +102,10 @@ func (*handler) GroupResources(dataFactory *models.Resource, resources []*models
 
 	// move all resources in the adf into the box
 	node.FillResourcesInBox(box, nodesToMove, diagram.Padding, true)
-	node.SetIconRelativeTo(dataFactoryGroup, box, node.BOTTOM_LEFT)
 
-	return []*node.Node{box}
+	if err := node.SetIconRelativeTo(dataFactoryGroup, box, node.BOTTOM_LEFT); err != nil {
+		return nil, err
+	}
+
+	return []*node.Node{box}, nil
 }
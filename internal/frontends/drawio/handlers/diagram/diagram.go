@@ -3,9 +3,9 @@ package diagram
 import (
 	"bufio"
 	"bytes"
-	"cloudsketch/internal/guid"
 	"fmt"
-	"os"
+	"html"
+	"io"
 )
 
 const (
@@ -13,48 +13,63 @@ const (
 )
 
 type diagram struct {
+	pages []*Page
+}
+
+// Page is a tab of the drawio file
+type Page struct {
+	id    string
+	name  string
 	cells []string
 }
 
-func New(cells []string) *diagram {
+func New(pages ...*Page) *diagram {
 	return &diagram{
+		pages: pages,
+	}
+}
+
+func NewPage(id, name string, cells []string) *Page {
+	return &Page{
+		id:    id,
+		name:  name,
 		cells: cells,
 	}
 }
 
-func (d *diagram) Write(filename string) error {
-	f, err := os.Create(filename)
+func (d *diagram) Write(writer io.Writer) error {
+	var buffer bytes.Buffer
+
+	for _, page := range d.pages {
+		buffer.WriteString(page.toDiagram())
+	}
+
+	w := bufio.NewWriter(writer)
+	_, err := w.WriteString(fmt.Sprintf(`<mxfile host="Electron" agent="Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) draw.io/25.0.1 Chrome/128.0.6613.186 Electron/32.2.6 Safari/537.36" version="25.0.1">%s
+</mxfile>
+`, buffer.String()))
 
 	if err != nil {
 		return err
 	}
 
-	defer f.Close()
+	return w.Flush()
+}
 
+func (p *Page) toDiagram() string {
 	var buffer bytes.Buffer
 
-	for _, cell := range d.cells {
+	for _, cell := range p.cells {
 		buffer.WriteString(cell)
 	}
 
-	diagramId := guid.NewGuidAlphanumeric()
-
-	w := bufio.NewWriter(f)
-	_, err = w.WriteString(fmt.Sprintf(`<mxfile host="Electron" agent="Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) draw.io/25.0.1 Chrome/128.0.6613.186 Electron/32.2.6 Safari/537.36" version="25.0.1">
-	<diagram name="Page-1" id="%s">
+	return fmt.Sprintf(`
+	<diagram name="%s" id="%s">
 		<mxGraphModel dx="2074" dy="1196" grid="1" gridSize="10" guides="1" tooltips="1" connect="1" arrows="1" fold="1" page="1" pageScale="1" pageWidth="850" pageHeight="1100" math="0" shadow="0">
 			<root>
 				<mxCell id="0" />
 				<mxCell id="1" parent="0" />%s
 			</root>
 		</mxGraphModel>
-	</diagram>
-</mxfile>
-`, diagramId, buffer.String()))
-
-	if err != nil {
-		return err
-	}
-
-	return w.Flush()
+	</diagram>`, html.EscapeString(p.name), p.id, buffer.String())
 }
@@ -5,6 +5,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 	"cloudsketch/internal/list"
 )
 
@@ -21,7 +22,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -31,15 +32,15 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 
 	link := resource.GetLinkOrDefault()
 
-	return node.NewIcon(IMAGE, resource.Name, &geometry, link)
+	return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
 }
 
-func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
-	return nil
+func (*handler) PostProcessIcon(ids *guid.Sequence, resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
+	return nil, nil
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	arrows := node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{})
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	arrows := node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{})
 
 	peerings, ok := source.Properties["peerings"]
 
@@ -48,19 +49,14 @@ func (*handler) DrawDependencies(source *models.Resource, targets []*models.Reso
 	}
 
 	arrows = list.Fold(peerings, arrows, func(peering string, acc []*node.Arrow) []*node.Arrow {
-		return addDependencyToPeering(peering, source, resource_map)
+		return addDependencyToPeering(ids, peering, source, resource_map)
 	})
 
 	return arrows
 }
 
-func addDependencyToPeering(peering string, source *models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	expressRouteGatewaysWithPeering := []*node.ResourceAndNode{}
-	for _, r := range *resource_map {
-		expressRouteGatewaysWithPeering = append(expressRouteGatewaysWithPeering, r)
-	}
-
-	expressRouteGatewaysWithPeering = list.Filter(expressRouteGatewaysWithPeering, func(ran *node.ResourceAndNode) bool {
+func addDependencyToPeering(ids *guid.Sequence, peering string, source *models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	expressRouteGatewaysWithPeering := list.Filter(node.SortedResources(resource_map), func(ran *node.ResourceAndNode) bool {
 		if ran.Resource.Type != types.EXPRESS_ROUTE_GATEWAY {
 			return false
 		}
@@ -79,12 +75,12 @@ func addDependencyToPeering(peering string, source *models.Resource, resource_ma
 	sourceNode := (*resource_map)[source.Id].Node
 
 	arrows := list.Map(expressRouteGatewaysWithPeering, func(peering *node.ResourceAndNode) *node.Arrow {
-		return node.NewArrow(sourceNode.Id(), peering.Node.Id(), nil)
+		return node.NewArrow(ids, sourceNode.Id(), peering.Node.Id(), nil)
 	})
 
 	return arrows
 }
 
-func (*handler) GroupResources(_ *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
-	return []*node.Node{}
+func (*handler) GroupResources(ids *guid.Sequence, _ *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
+	return []*node.Node{}, nil
 }
@@ -5,6 +5,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 	"cloudsketch/internal/list"
 	"strings"
 )
@@ -22,7 +23,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -32,32 +33,37 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 
 	link := resource.GetLinkOrDefault()
 
-	return node.NewIcon(IMAGE, resource.Name, &geometry, link)
+	return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
 }
 
-func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
+func (*handler) PostProcessIcon(ids *guid.Sequence, resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
 	outboundSubnet, ok := resource.Resource.Properties["outboundSubnet"]
 
 	if !ok {
-		return nil
+		return nil, nil
 	}
 
-	outboundSubnetResource := (*resource_map)[outboundSubnet[0]]
+	outboundSubnetResource, ok := (*resource_map)[outboundSubnet[0]]
+
+	if !ok {
+		// the subnet was filtered out
+		return nil, nil
+	}
 
 	resource.Resource.DependsOn = append(resource.Resource.DependsOn, outboundSubnetResource.Resource)
 
-	return nil
+	return nil, nil
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	arrows := node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{})
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	arrows := node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{})
 
-	arrows = append(arrows, addDependencyToAssociatedStorageAccount(source, resource_map)...)
+	arrows = append(arrows, addDependencyToAssociatedStorageAccount(ids, source, resource_map)...)
 
 	return arrows
 }
 
-func addDependencyToAssociatedStorageAccount(source *models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+func addDependencyToAssociatedStorageAccount(ids *guid.Sequence, source *models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
 	// this property only contains the name of the storage account
 	// so it has to be uniquely identified among all storage accounts
 	storageAccountName, ok := source.Properties["storageAccountName"]
@@ -66,20 +72,20 @@ func addDependencyToAssociatedStorageAccount(source *models.Resource, resource_m
 		return []*node.Arrow{}
 	}
 
-	resources := []*node.ResourceAndNode{}
-	for _, r := range *resource_map {
-		resources = append(resources, r)
-	}
-
-	resources = list.Filter(resources, func(ran *node.ResourceAndNode) bool {
+	resources := list.Filter(node.SortedResources(resource_map), func(ran *node.ResourceAndNode) bool {
 		return ran.Resource.Type == types.STORAGE_ACCOUNT && strings.Contains(ran.Resource.Name, storageAccountName[0])
 	})
 
+	if len(resources) == 0 {
+		// the storage account was filtered out, or is drawn on another page
+		return []*node.Arrow{}
+	}
+
 	sourceNode := (*resource_map)[source.Id].Node
 
-	return []*node.Arrow{node.NewArrow(sourceNode.Id(), resources[0].Node.Id(), nil)}
+	return []*node.Arrow{node.NewArrow(ids, sourceNode.Id(), resources[0].Node.Id(), nil)}
 }
 
-func (*handler) GroupResources(_ *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
-	return []*node.Node{}
+func (*handler) GroupResources(ids *guid.Sequence, _ *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
+	return []*node.Node{}, nil
 }
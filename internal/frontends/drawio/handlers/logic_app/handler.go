@@ -5,6 +5,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 )
 
 type handler struct{}
@@ -20,7 +21,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -30,22 +31,22 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 
 	link := resource.GetLinkOrDefault()
 
-	return node.NewIcon(IMAGE, resource.Name, &geometry, link)
+	return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
 }
 
-func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
-	return node.HandlePrivateEndpoint(resource, resource_map)
+func (*handler) PostProcessIcon(ids *guid.Sequence, resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
+	return node.HandlePrivateEndpoint(ids, resource, resource_map)
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	arrows := node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{types.SUBNET})
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	arrows := node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{types.SUBNET})
 
-	arrows = append(arrows, addDependencyToOutboundSubnet(source, resource_map)...)
+	arrows = append(arrows, addDependencyToOutboundSubnet(ids, source, resource_map)...)
 
 	return arrows
 }
 
-func addDependencyToOutboundSubnet(source *models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+func addDependencyToOutboundSubnet(ids *guid.Sequence, source *models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
 	dashed := "dashed=1"
 
 	outboundSubnet, ok := source.Properties["outboundSubnet"]
@@ -54,13 +55,20 @@ func addDependencyToOutboundSubnet(source *models.Resource, resource_map *map[st
 		return []*node.Arrow{}
 	}
 
-	outboundSubnetNode := (*resource_map)[outboundSubnet[0]].Node
+	outboundSubnetResource, ok := (*resource_map)[outboundSubnet[0]]
+
+	if !ok {
+		// the subnet was filtered out
+		return []*node.Arrow{}
+	}
+
+	outboundSubnetNode := outboundSubnetResource.Node
 
 	sourceNode := (*resource_map)[source.Id].Node
 
-	return []*node.Arrow{node.NewArrow(sourceNode.Id(), outboundSubnetNode.Id(), &dashed)}
+	return []*node.Arrow{node.NewArrow(ids, sourceNode.Id(), outboundSubnetNode.Id(), &dashed)}
 }
 
-func (*handler) GroupResources(_ *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
-	return []*node.Node{}
+func (*handler) GroupResources(ids *guid.Sequence, _ *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
+	return []*node.Node{}, nil
 }
@@ -5,6 +5,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 	"cloudsketch/internal/list"
 )
 
@@ -21,7 +22,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -31,14 +32,14 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 
 	link := resource.GetLinkOrDefault()
 
-	return node.NewIcon(IMAGE, resource.Name, &geometry, link)
+	return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
 }
 
-func (*handler) PostProcessIcon(nic *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
+func (*handler) PostProcessIcon(ids *guid.Sequence, nic *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
 	attachedToIds, ok := nic.Resource.Properties["attachedTo"]
 
 	if !ok {
-		return nil
+		return nil, nil
 	}
 
 	attachedTo, ok := (*resource_map)[attachedToIds[0]]
@@ -46,18 +47,18 @@ func (*handler) PostProcessIcon(nic *node.ResourceAndNode, resource_map *map[str
 	// dont draw NICs if they are attached to a blacklisted resource
 	if !ok || isBlacklistedResource(attachedTo.Resource.Type) {
 		delete(*resource_map, nic.Resource.Id)
-		return nil
+		return nil, nil
 	}
 
 	existingNics := getNICsPointingToResource(resource_map, attachedTo.Resource)
 
 	// multiple NICs point to the same resource - skip
 	if len(existingNics) > 1 {
-		return nil
+		return nil, nil
 	}
 
 	// set icon top right
-	return node.GroupIconsAndSetPosition(attachedTo.Node, nic.Node, node.TOP_RIGHT)
+	return node.GroupIconsAndSetPosition(ids, attachedTo.Node, nic.Node, node.TOP_RIGHT)
 }
 
 func isBlacklistedResource(resourceType string) bool {
@@ -72,7 +73,7 @@ func getNICsPointingToResource(resource_map *map[string]*node.ResourceAndNode, a
 	nics := []*models.Resource{}
 
 	// figure out how many private endpoints are pointing to the storage account
-	for _, v := range *resource_map {
+	for _, v := range node.SortedResources(resource_map) {
 		// filter out the private endpoints
 		if v.Resource.Type != types.NETWORK_INTERFACE {
 			continue
@@ -97,10 +98,10 @@ func getNICsPointingToResource(resource_map *map[string]*node.ResourceAndNode, a
 	return nics
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	return node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{types.SUBNET})
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	return node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{types.SUBNET})
 }
 
-func (*handler) GroupResources(_ *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
-	return []*node.Node{}
+func (*handler) GroupResources(ids *guid.Sequence, _ *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
+	return []*node.Node{}, nil
 }
@@ -5,6 +5,8 @@ import (
 	"cloudsketch/internal/guid"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -13,9 +15,9 @@ type Arrow struct {
 	source, target string
 }
 
-func NewArrow(source, target string, style *string) *Arrow {
+func NewArrow(ids *guid.Sequence, source, target string, style *string) *Arrow {
 	values := map[string]any{
-		"id":     guid.NewGuidAlphanumeric(),
+		"id":     ids.Next(),
 		"source": source,
 		"target": target,
 		"style":  "edgeStyle=orthogonalEdgeStyle;rounded=0;orthogonalLoop=1;jettySize=auto;html=1;jumpStyle=arc",
@@ -34,11 +36,34 @@ func NewArrow(source, target string, style *string) *Arrow {
 	}
 }
 
+func (n *Arrow) Source() string {
+	return n.source
+}
+
+func (n *Arrow) Target() string {
+	return n.target
+}
+
+func (n *Arrow) SetProperty(property, value string) {
+	n.values[property] = value
+}
+
+// GetProperty returns a value of the cell, like its style
+func (n *Arrow) GetProperty(property string) string {
+	value, ok := n.values[property]
+
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
 func (n *Arrow) ToMXCell() string {
 	var buffer bytes.Buffer
 
-	for k, v := range n.values {
-		j, _ := json.Marshal(v)
+	for _, k := range slices.Sorted(maps.Keys(n.values)) {
+		j, _ := json.Marshal(n.values[k])
 
 		buffer.WriteString(fmt.Sprintf("%s=%v ", k, string(j)))
 	}
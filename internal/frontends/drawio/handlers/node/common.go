@@ -4,8 +4,9 @@ import (
 	"cloudsketch/internal/frontends/drawio/handlers/diagram"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 	"cloudsketch/internal/list"
-	"log"
+	"fmt"
 	"math"
 	"sort"
 )
@@ -21,12 +22,12 @@ var (
 	STYLE = "rounded=0;whiteSpace=wrap;html=1;dashed=1;opacity=50;"
 )
 
-func GroupIconsAndSetPosition(centerIcon, cornerIcon *Node, position int) *Node {
+func GroupIconsAndSetPosition(ids *guid.Sequence, centerIcon, cornerIcon *Node, position int) (*Node, error) {
 	centerIconGeometry := centerIcon.GetGeometry()
 	cornerIconGeometry := cornerIcon.GetGeometry()
 
 	// create a group on top of the referenced node, IMPORTANT: copy the geometry to avoid using the same reference
-	group := NewGroup(&Geometry{
+	group := NewGroup(ids, &Geometry{
 		X:      centerIconGeometry.X,
 		Y:      centerIconGeometry.Y,
 		Width:  centerIconGeometry.Width,
@@ -45,12 +46,14 @@ func GroupIconsAndSetPosition(centerIcon, cornerIcon *Node, position int) *Node
 	cornerIcon.SetDimensions(widthScaled, heightScaled)
 	cornerIcon.ContainedIn = group
 
-	SetIconRelativeTo(cornerIcon, centerIcon, position)
+	if err := SetIconRelativeTo(cornerIcon, centerIcon, position); err != nil {
+		return nil, err
+	}
 
-	return group
+	return group, nil
 }
 
-func SetIconRelativeTo(iconToMove *Node, relativeTo *Node, position int) {
+func SetIconRelativeTo(iconToMove *Node, relativeTo *Node, position int) error {
 	relativeToGeometry := relativeTo.GetGeometry()
 	iconToMoveGeometry := iconToMove.GetGeometry()
 
@@ -83,10 +86,12 @@ func SetIconRelativeTo(iconToMove *Node, relativeTo *Node, position int) {
 			break
 		}
 	default:
-		log.Fatalf("Undefined position %v", position)
+		return fmt.Errorf("unknown icon position %v", position)
 	}
 
 	iconToMove.SetPosition(x-iconToMoveGeometry.Width/2, y-iconToMoveGeometry.Height/2)
+
+	return nil
 }
 
 func FillResourcesInBox(box *Node, resourcesInGrouping []*Node, padding int, setResourceParent bool) {
@@ -184,10 +189,10 @@ func getChildResources(resources []*models.Resource, parentId string, childType
 	return childResources
 }
 
-func BoxResources(parent *Node, children []*ResourceAndNode) *Node {
+func BoxResources(ids *guid.Sequence, parent *Node, children []*ResourceAndNode) (*Node, error) {
 	parentGeometry := parent.GetGeometry()
 
-	box := NewBox(&Geometry{
+	box := NewBox(ids, &Geometry{
 		X:      parentGeometry.X,
 		Y:      parentGeometry.Y,
 		Width:  0,
@@ -196,6 +201,7 @@ func BoxResources(parent *Node, children []*ResourceAndNode) *Node {
 
 	parent.SetProperty("parent", box.Id())
 	parent.ContainedIn = box
+	parent.Box = box
 	parent.SetPosition(0, 0)
 
 	nodesToMove := list.Map(children, func(r *ResourceAndNode) *Node {
@@ -206,12 +212,14 @@ func BoxResources(parent *Node, children []*ResourceAndNode) *Node {
 	FillResourcesInBox(box, nodesToMove, diagram.Padding, true)
 
 	parent.SetDimensions(parentGeometry.Width/2, parentGeometry.Height/2)
-	SetIconRelativeTo(parent, box, BOTTOM_LEFT)
+	if err := SetIconRelativeTo(parent, box, BOTTOM_LEFT); err != nil {
+		return nil, err
+	}
 
-	return box
+	return box, nil
 }
 
-func DrawDependencyArrowsToTargets(source *models.Resource, targets []*models.Resource, resource_map *map[string]*ResourceAndNode, typeBlacklist []string) []*Arrow {
+func DrawDependencyArrowsToTargets(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*ResourceAndNode, typeBlacklist []string) []*Arrow {
 	// don't draw arrows to subscriptions
 	typeBlacklist = append(typeBlacklist, types.SUBSCRIPTION, types.VIRTUAL_NETWORK, types.SUBNET)
 
@@ -229,26 +237,25 @@ func DrawDependencyArrowsToTargets(source *models.Resource, targets []*models.Re
 	sourceNode := (*resource_map)[source.Id].Node
 
 	arrows := list.Fold(targetResources, []*Arrow{}, func(target *ResourceAndNode, acc []*Arrow) []*Arrow {
-		return append(acc, NewArrow(sourceNode.Id(), target.Node.Id(), nil))
+		return append(acc, NewArrow(ids, sourceNode.Id(), target.Node.Id(), nil))
 	})
 
 	return arrows
 }
 
-func HandlePrivateEndpoint(resource *ResourceAndNode, resource_map *map[string]*ResourceAndNode) *Node {
+func HandlePrivateEndpoint(ids *guid.Sequence, resource *ResourceAndNode, resource_map *map[string]*ResourceAndNode) (*Node, error) {
 	privateEndpoints := getPrivateEndpointPointingToResource(resource_map, resource.Resource)
 
 	if len(privateEndpoints) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	if len(privateEndpoints) > 1 {
 		// multiple private endpoints point to this resource. If they all
 		// belong to the same subnet they can be merged
-		resources := []*models.Resource{}
-		for _, e := range *resource_map {
-			resources = append(resources, e.Resource)
-		}
+		resources := list.Map(SortedResources(resource_map), func(e *ResourceAndNode) *models.Resource {
+			return e.Resource
+		})
 
 		firstSubnet := getPrivateEndpointSubnet(privateEndpoints[0].Resource, resources)
 
@@ -259,7 +266,7 @@ func HandlePrivateEndpoint(resource *ResourceAndNode, resource_map *map[string]*
 		})
 
 		if !allPrivateEndpointsInSameSubnet {
-			return nil
+			return nil, nil
 		}
 
 		// delete unneeded private endpoint icons
@@ -273,7 +280,7 @@ func HandlePrivateEndpoint(resource *ResourceAndNode, resource_map *map[string]*
 	}
 
 	// one private endpoint exists, "merge" the two icons
-	return GroupIconsAndSetPosition(resource.Node, privateEndpoints[0].Node, TOP_RIGHT)
+	return GroupIconsAndSetPosition(ids, resource.Node, privateEndpoints[0].Node, TOP_RIGHT)
 }
 
 func getPrivateEndpointSubnet(resource *models.Resource, resources []*models.Resource) *string {
@@ -298,7 +305,7 @@ func getPrivateEndpointPointingToResource(resource_map *map[string]*ResourceAndN
 	privateEndpoints := []*ResourceAndNode{}
 
 	// figure out how many private endpoints are pointing to the storage account
-	for _, v := range *resource_map {
+	for _, v := range SortedResources(resource_map) {
 		// filter out the private endpoints
 		if v.Resource.Type != types.PRIVATE_ENDPOINT {
 			continue
@@ -0,0 +1,47 @@
+package node
+
+import (
+	"cloudsketch/internal/guid"
+	"testing"
+)
+
+func icons(ids *guid.Sequence) (*Node, *Node) {
+	center := NewIcon(ids, "center.svg", "center", &Geometry{X: 0, Y: 0, Width: 100, Height: 80}, nil)
+	corner := NewIcon(ids, "corner.svg", "corner", &Geometry{X: 0, Y: 0, Width: 20, Height: 20}, nil)
+
+	return center, corner
+}
+
+func TestSetIconRelativeTo(t *testing.T) {
+	for position, expected := range map[int][2]int{
+		TOP_LEFT:     {-10, -10},
+		TOP_RIGHT:    {90, -10},
+		BOTTOM_LEFT:  {-10, 70},
+		BOTTOM_RIGHT: {90, 70},
+	} {
+		center, corner := icons(guid.NewSequence("test"))
+
+		if err := SetIconRelativeTo(corner, center, position); err != nil {
+			t.Fatal(err)
+		}
+
+		geometry := corner.GetGeometry()
+
+		if geometry.X != expected[0] || geometry.Y != expected[1] {
+			t.Errorf("position %v: expected %v, got (%v, %v)", position, expected, geometry.X, geometry.Y)
+		}
+	}
+}
+
+func TestUnknownPosition(t *testing.T) {
+	ids := guid.NewSequence("test")
+	center, corner := icons(ids)
+
+	if err := SetIconRelativeTo(corner, center, 4); err == nil {
+		t.Error("expected an error for an unknown position")
+	}
+
+	if _, err := GroupIconsAndSetPosition(ids, center, corner, -1); err == nil {
+		t.Error("expected grouping with an unknown position to fail")
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"cloudsketch/internal/guid"
 	"encoding/json"
 	"fmt"
+	"html"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -13,9 +16,15 @@ type Node struct {
 	values      map[string]any
 	geometry    *Geometry
 	ContainedIn *Node
+	// the box drawn around the resources grouped by this node, if any
+	Box *Node
+	// attributes shown in drawio when hovering the cell and in Edit Data
+	metadata map[string]string
+	// name of the resource, replaced with a placeholder in the label
+	name string
 }
 
-func NewIcon(image, label string, geometry *Geometry, link *string) *Node {
+func NewIcon(ids *guid.Sequence, image, label string, geometry *Geometry, link *string) *Node {
 	values := map[string]any{
 		"style": fmt.Sprintf("image;aspect=fixed;html=1;points=[];align=center;fontSize=12;image=%s;labelBackgroundColor=none;", image),
 		"value": label,
@@ -25,10 +34,10 @@ func NewIcon(image, label string, geometry *Geometry, link *string) *Node {
 		values["link"] = *link
 	}
 
-	return NewGeneric(values, geometry)
+	return NewGeneric(ids, values, geometry)
 }
 
-func NewBox(geometry *Geometry, style *string) *Node {
+func NewBox(ids *guid.Sequence, geometry *Geometry, style *string) *Node {
 	values := map[string]any{
 		"value": "",
 		"style": "rounded=0;whiteSpace=wrap;html=1;",
@@ -38,21 +47,22 @@ func NewBox(geometry *Geometry, style *string) *Node {
 		values["style"] = fmt.Sprintf("%s;%s", values["style"], *style)
 	}
 
-	return NewGeneric(values, geometry)
+	return NewGeneric(ids, values, geometry)
 }
 
-func NewGroup(geometry *Geometry) *Node {
+func NewGroup(ids *guid.Sequence, geometry *Geometry) *Node {
 	values := map[string]any{
 		"value":       "",
 		"style":       "group",
 		"connectable": "0",
 	}
 
-	return NewGeneric(values, geometry)
+	return NewGeneric(ids, values, geometry)
 }
 
-func NewGeneric(values map[string]any, geometry *Geometry) *Node {
-	id := guid.NewGuidAlphanumeric()
+// NewGeneric creates a cell with the next id of the sequence. Every diagram draws its ids from its own sequence, so the same resources always render to the same diagram
+func NewGeneric(ids *guid.Sequence, values map[string]any, geometry *Geometry) *Node {
+	id := ids.Next()
 
 	values["id"] = id
 	values["parent"] = "1"
@@ -73,6 +83,36 @@ func (n *Node) SetProperty(property, value string) {
 	n.values[property] = value
 }
 
+// GetProperty returns a value of the cell, like its style, label or parent
+func (n *Node) GetProperty(property string) string {
+	value, ok := n.values[property]
+
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+// SetName attaches the name of the resource to the cell, so the label follows it when it is renamed in Edit Data
+func (n *Node) SetName(name string) {
+	n.name = name
+	n.SetMetadata("name", html.EscapeString(name))
+}
+
+// SetMetadata attaches data to the cell. The value must already be escaped
+func (n *Node) SetMetadata(key, value string) {
+	if n.metadata == nil {
+		n.metadata = map[string]string{}
+	}
+
+	n.metadata[key] = value
+}
+
+func (n *Node) AddStyle(style string) {
+	n.values["style"] = fmt.Sprintf("%s;%s", n.values["style"], style)
+}
+
 func (n *Node) SetPosition(x, y int) {
 	n.geometry.X = x
 	n.geometry.Y = y
@@ -98,22 +138,23 @@ func (n *Node) GetParentOrThis() *Node {
 func (n *Node) ToMXCell() string {
 	var buffer bytes.Buffer
 
-	for k, v := range n.values {
-		j, _ := json.Marshal(v)
+	for _, k := range slices.Sorted(maps.Keys(n.values)) {
+		j, _ := json.Marshal(n.values[k])
 
 		buffer.WriteString(fmt.Sprintf("%s=%v ", k, string(j)))
 	}
 
-	link, ok := n.values["link"]
+	_, hasLink := n.values["link"]
+	_, hasTooltip := n.values["tooltip"]
 
-	if ok {
-		// cell has a link attached. DrawIO requires these to be nested in a 'UserObject'
+	if hasLink || hasTooltip || len(n.metadata) > 0 {
+		// cell has a link, tooltip or data attached. DrawIO requires these to be nested in a 'UserObject'
 		cell := fmt.Sprintf(`
-				<UserObject label="%s" link="%s" id="%s">
+				<UserObject label="%s"%s id="%s">
 	        		<mxCell style="%s" parent="%s" vertex="%s">
         				<mxGeometry x="%v" y="%v" width="%v" height="%v" as="geometry" />
         			</mxCell>
-				</UserObject>`, n.values["value"], link, n.id, n.values["style"], n.values["parent"], n.values["vertex"], n.geometry.X, n.geometry.Y, n.geometry.Width, n.geometry.Height)
+				</UserObject>`, html.EscapeString(n.label()), n.userObjectAttributes(), n.id, n.values["style"], n.values["parent"], n.values["vertex"], n.geometry.X, n.geometry.Y, n.geometry.Width, n.geometry.Height)
 
 		return cell
 	}
@@ -126,6 +167,40 @@ func (n *Node) ToMXCell() string {
 	return cell
 }
 
+func (n *Node) userObjectAttributes() string {
+	var buffer bytes.Buffer
+
+	if link, ok := n.values["link"]; ok {
+		buffer.WriteString(fmt.Sprintf(` link="%s"`, link))
+	}
+
+	if tooltip, ok := n.values["tooltip"]; ok {
+		// tooltips are already escaped as they may contain line breaks
+		buffer.WriteString(fmt.Sprintf(` tooltip="%s"`, tooltip))
+	}
+
+	if n.label() != n.GetProperty("value") {
+		buffer.WriteString(` placeholders="1"`)
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(n.metadata)) {
+		buffer.WriteString(fmt.Sprintf(` %s="%s"`, k, n.metadata[k]))
+	}
+
+	return buffer.String()
+}
+
+// label returns the label of the cell. The name of a resource is replaced with a placeholder, so renaming it in Edit Data updates the label
+func (n *Node) label() string {
+	label := n.GetProperty("value")
+
+	if n.name == "" || !strings.Contains(label, n.name) {
+		return label
+	}
+
+	return strings.Replace(label, n.name, "%name%", 1)
+}
+
 func ToMXCell(n *Node) string {
 	return n.ToMXCell()
 }
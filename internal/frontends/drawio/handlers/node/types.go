@@ -1,6 +1,11 @@
 package node
 
-import "cloudsketch/internal/frontends/models"
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/list"
+	"maps"
+	"slices"
+)
 
 type Geometry struct {
 	X, Y, Width, Height int
@@ -10,3 +15,10 @@ type ResourceAndNode struct {
 	Resource *models.Resource
 	Node     *Node
 }
+
+// SortedResources returns the resources of the map ordered by id, iterating the map directly would make the diagram differ between runs
+func SortedResources(resource_map *map[string]*ResourceAndNode) []*ResourceAndNode {
+	return list.Map(slices.Sorted(maps.Keys(*resource_map)), func(id string) *ResourceAndNode {
+		return (*resource_map)[id]
+	})
+}
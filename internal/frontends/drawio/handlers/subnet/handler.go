@@ -7,6 +7,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 	"cloudsketch/internal/list"
 	"fmt"
 )
@@ -28,7 +29,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -36,13 +37,18 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 		Height: HEIGHT,
 	}
 
-	subnetSize := resource.Properties["size"][0]
-
-	name := fmt.Sprintf("%s/%s", resource.Name, subnetSize)
+	subnetSize, ok := resource.Properties["size"]
 
 	link := resource.GetLinkOrDefault()
 
-	return node.NewIcon(IMAGE, name, &geometry, link)
+	// subnets with multiple address ranges have no single size
+	if !ok {
+		return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
+	}
+
+	name := fmt.Sprintf("%s/%s", resource.Name, subnetSize[0])
+
+	return node.NewIcon(ids, IMAGE, name, &geometry, link)
 }
 
 func getResourcseOfType(resource *models.Resource, resource_map *map[string]*node.ResourceAndNode, typ string) []*models.Resource {
@@ -57,14 +63,20 @@ func getResourcseOfType(resource *models.Resource, resource_map *map[string]*nod
 	})
 }
 
-func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
+func (*handler) PostProcessIcon(ids *guid.Sequence, resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
 	var parentGroup *node.Node = nil
 
 	routeTables := getResourcseOfType(resource.Resource, resource_map, types.ROUTE_TABLE)
 	if len(routeTables) == 1 {
 		routeTable := (*resource_map)[routeTables[0].Id]
 
-		parentGroup = node.GroupIconsAndSetPosition(resource.Node, routeTable.Node, node.TOP_LEFT)
+		group, err := node.GroupIconsAndSetPosition(ids, resource.Node, routeTable.Node, node.TOP_LEFT)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parentGroup = group
 	}
 
 	networkSecurityGroups := getResourcseOfType(resource.Resource, resource_map, types.NETWORK_SECURITY_GROUP)
@@ -74,12 +86,12 @@ func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *ma
 
 		// other subnets might point to the same NSG. If they do, ignore the merging
 		if snets := resourcesWithReferencesTo(resource_map, networkSecurityGroup.Resource.Id); snets != 1 {
-			return parentGroup
+			return parentGroup, nil
 		}
 
 		if parentGroup == nil {
 			// route table icon was not set
-			return node.GroupIconsAndSetPosition(resource.Node, networkSecurityGroup.Node, node.TOP_RIGHT)
+			return node.GroupIconsAndSetPosition(ids, resource.Node, networkSecurityGroup.Node, node.TOP_RIGHT)
 		}
 
 		// route table icon was set
@@ -88,12 +100,15 @@ func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *ma
 		networkSecurityGroup.Node.SetProperty("parent", parentGroup.Id())
 		networkSecurityGroup.Node.SetDimensions(networkSecurityGroupGeometry.Width/2, networkSecurityGroupGeometry.Width/2)
 
-		node.SetIconRelativeTo(networkSecurityGroup.Node, resource.Node.GetParentOrThis(), node.TOP_RIGHT)
+		if err := node.SetIconRelativeTo(networkSecurityGroup.Node, resource.Node.GetParentOrThis(), node.TOP_RIGHT); err != nil {
+			return nil, err
+		}
+
 		networkSecurityGroup.Node.ContainedIn = parentGroup
 		networkSecurityGroup.Node.SetProperty("value", "")
 	}
 
-	return parentGroup
+	return parentGroup, nil
 }
 
 func resourcesWithReferencesTo(resource_map *map[string]*node.ResourceAndNode, resourceId string) int {
@@ -110,7 +125,7 @@ func resourcesWithReferencesTo(resource_map *map[string]*node.ResourceAndNode, r
 	return count
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
 	// if the subnet has a NSG attached that only points to this subnet, don't draw an arrow
 	targets = list.Filter(targets, func(target *models.Resource) bool {
 		if target.Type != types.NETWORK_SECURITY_GROUP && target.Type != types.ROUTE_TABLE {
@@ -122,14 +137,14 @@ func (*handler) DrawDependencies(source *models.Resource, targets []*models.Reso
 		return nsgSubnetReferences != 1
 	})
 
-	return node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{types.VIRTUAL_NETWORK})
+	return node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{types.VIRTUAL_NETWORK})
 }
 
-func (*handler) GroupResources(subnet *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
+func (*handler) GroupResources(ids *guid.Sequence, subnet *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
 	resourcesInSubnet := node.GetChildResources(resources, subnet.Id, resource_map)
 
 	if len(resourcesInSubnet) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	nodes := list.Map(resourcesInSubnet, func(ran *node.ResourceAndNode) *node.Node {
@@ -155,18 +170,22 @@ func (*handler) GroupResources(subnet *models.Resource, resources []*models.Reso
 	// subnets can be in a group because of UDRs
 	subnetNode = subnetNode.GetParentOrThis()
 
-	box := node.NewBox(&node.Geometry{
+	box := node.NewBox(ids, &node.Geometry{
 		X:      0,
 		Y:      0,
 		Width:  0,
 		Height: 0,
 	}, &STYLE)
 
+	(*resource_map)[subnet.Id].Node.Box = box
+
 	subnetNode.SetProperty("parent", box.Id())
 	subnetNode.ContainedIn = box
-	node.SetIconRelativeTo(subnetNode, box, node.TOP_LEFT)
+	if err := node.SetIconRelativeTo(subnetNode, box, node.TOP_LEFT); err != nil {
+		return nil, err
+	}
 
 	node.FillResourcesInBox(box, nodes, diagram.Padding, true)
 
-	return []*node.Node{box}
+	return []*node.Node{box}, nil
 }
@@ -7,6 +7,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 	"cloudsketch/internal/list"
 )
 
@@ -23,7 +24,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -33,19 +34,18 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 
 	link := resource.GetLinkOrDefault()
 
-	return node.NewIcon(IMAGE, resource.Name, &geometry, link)
+	return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
 }
 
-func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
-	return nil
-
+func (*handler) PostProcessIcon(ids *guid.Sequence, resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
+	return nil, nil
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	return node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{})
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	return node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{})
 }
 
-func (*handler) GroupResources(resource *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
+func (*handler) GroupResources(ids *guid.Sequence, resource *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
 	subscriptionResources := getAllResourcesInSubscription(resource.Id, resources, resource_map)
 
 	// a subscription can contain resources that belong to the same group, these needs to be filtered to
@@ -64,7 +64,7 @@ func (*handler) GroupResources(resource *models.Resource, resources []*models.Re
 
 	subscriptionNode := (*resource_map)[resource.Id].Node
 
-	box := node.NewBox(&node.Geometry{
+	box := node.NewBox(ids, &node.Geometry{
 		X:      0,
 		Y:      0,
 		Width:  0,
@@ -75,9 +75,12 @@ func (*handler) GroupResources(resource *models.Resource, resources []*models.Re
 
 	subscriptionNode.SetProperty("parent", box.Id())
 	subscriptionNode.ContainedIn = box
-	node.SetIconRelativeTo(subscriptionNode, box, node.TOP_LEFT)
+	subscriptionNode.Box = box
+	if err := node.SetIconRelativeTo(subscriptionNode, box, node.TOP_LEFT); err != nil {
+		return nil, err
+	}
 
-	return []*node.Node{box}
+	return []*node.Node{box}, nil
 }
 
 func getAllResourcesInSubscription(resourceId string, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
@@ -7,6 +7,7 @@ import (
 	"cloudsketch/internal/frontends/drawio/images"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 	"cloudsketch/internal/list"
 	"fmt"
 )
@@ -28,7 +29,7 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) MapResource(resource *models.Resource) *node.Node {
+func (*handler) MapResource(ids *guid.Sequence, resource *models.Resource) *node.Node {
 	geometry := node.Geometry{
 		X:      0,
 		Y:      0,
@@ -41,27 +42,27 @@ func (*handler) MapResource(resource *models.Resource) *node.Node {
 	link := resource.GetLinkOrDefault()
 
 	if !ok {
-		return node.NewIcon(IMAGE, resource.Name, &geometry, link)
+		return node.NewIcon(ids, IMAGE, resource.Name, &geometry, link)
 	}
 
 	name := fmt.Sprintf("%s/%s", resource.Name, vnetSize[0])
 
-	return node.NewIcon(IMAGE, name, &geometry, link)
+	return node.NewIcon(ids, IMAGE, name, &geometry, link)
 }
 
-func (*handler) PostProcessIcon(resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) *node.Node {
-	return nil
+func (*handler) PostProcessIcon(ids *guid.Sequence, resource *node.ResourceAndNode, resource_map *map[string]*node.ResourceAndNode) (*node.Node, error) {
+	return nil, nil
 }
 
-func (*handler) DrawDependencies(source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
-	return node.DrawDependencyArrowsToTargets(source, targets, resource_map, []string{})
+func (*handler) DrawDependencies(ids *guid.Sequence, source *models.Resource, targets []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	return node.DrawDependencyArrowsToTargets(ids, source, targets, resource_map, []string{})
 }
 
-func (*handler) GroupResources(vnet *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
+func (*handler) GroupResources(ids *guid.Sequence, vnet *models.Resource, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
 	resourcesInVnet := getAllResourcesInVnet(vnet.Id, resources, resource_map)
 
 	if len(resourcesInVnet) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// a vnet can contain resources that belong to the same group, these needs to be filtered to
@@ -80,7 +81,7 @@ func (*handler) GroupResources(vnet *models.Resource, resources []*models.Resour
 
 	vnetNode := (*resource_map)[vnet.Id].Node
 
-	box := node.NewBox(&node.Geometry{
+	box := node.NewBox(ids, &node.Geometry{
 		X:      0,
 		Y:      0,
 		Width:  0,
@@ -91,9 +92,12 @@ func (*handler) GroupResources(vnet *models.Resource, resources []*models.Resour
 
 	vnetNode.SetProperty("parent", box.Id())
 	vnetNode.ContainedIn = box
-	node.SetIconRelativeTo(vnetNode, box, node.BOTTOM_LEFT)
+	vnetNode.Box = box
+	if err := node.SetIconRelativeTo(vnetNode, box, node.BOTTOM_LEFT); err != nil {
+		return nil, err
+	}
 
-	return []*node.Node{box}
+	return []*node.Node{box}, nil
 }
 
 func getAllResourcesInVnet(vnetId string, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
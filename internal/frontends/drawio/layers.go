@@ -0,0 +1,164 @@
+package drawio
+
+import (
+	"cloudsketch/internal/frontends/drawio/handlers/node"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/list"
+	"fmt"
+	"html"
+)
+
+const (
+	// arrows get a layer of their own, next to the resource categories
+	LAYER_DEPENDENCIES = "dependencies"
+)
+
+var (
+	// layers from back to front
+	LAYERS = []*Layer{
+		{Id: "layer-scope", Category: types.CATEGORY_SCOPE, Name: "Subscriptions"},
+		{Id: "layer-network", Category: types.CATEGORY_NETWORK, Name: "Networking"},
+		{Id: "layer-compute", Category: types.CATEGORY_COMPUTE, Name: "Compute"},
+		{Id: "layer-data", Category: types.CATEGORY_DATA, Name: "Data"},
+		{Id: "layer-integration", Category: types.CATEGORY_INTEGRATION, Name: "Integration"},
+		{Id: "layer-security", Category: types.CATEGORY_SECURITY, Name: "Identity and security"},
+		{Id: "layer-monitoring", Category: types.CATEGORY_MONITORING, Name: "Monitoring"},
+		{Id: "layer-dependencies", Category: LAYER_DEPENDENCIES, Name: "Dependencies"},
+	}
+)
+
+// Layer can be toggled on and off in drawio
+type Layer struct {
+	Id       string
+	Category string
+	Name     string
+}
+
+func (l *Layer) ToMXCell() string {
+	return fmt.Sprintf(`
+				<mxCell id="%s" value="%s" parent="0" />`, l.Id, html.EscapeString(l.Name))
+}
+
+// assignLayers moves the boxes, groups and icons that are not part of a group to the layer of their category.
+// A cell is on the layer of its parent, so boxes can no longer be the parent of the resources in them and every cell on a layer is positioned absolutely
+func assignLayers(layout *Layout, resource_map *map[string]*node.ResourceAndNode, overrides map[string]string) {
+	byId := map[string]*node.Node{}
+	boxes := map[string]bool{}
+
+	for _, n := range layout.Nodes() {
+		byId[n.Id()] = n
+	}
+
+	for _, box := range layout.Boxes {
+		boxes[box.Id()] = true
+	}
+
+	// positions are computed before any cell is moved
+	positions := map[string][2]int{}
+
+	for _, n := range layout.Nodes() {
+		x, y := absolutePosition(n, byId)
+		positions[n.Id()] = [2]int{x, y}
+	}
+
+	categories := cellCategories(resource_map, byId, boxes, overrides)
+	layers := map[string]*Layer{}
+
+	for _, layer := range LAYERS {
+		layers[layer.Category] = layer
+	}
+
+	used := map[string]bool{}
+
+	for _, n := range layout.Nodes() {
+		parent := n.GetProperty("parent")
+
+		// cells in a group move along with the group
+		if parent != "1" && !boxes[parent] {
+			continue
+		}
+
+		layer, ok := layers[categories[n.Id()]]
+
+		if !ok {
+			// unknown types stay on the default layer
+			continue
+		}
+
+		n.SetPosition(positions[n.Id()][0], positions[n.Id()][1])
+		n.SetProperty("parent", layer.Id)
+		used[layer.Id] = true
+	}
+
+	for _, arrow := range layout.Arrows {
+		arrow.SetProperty("parent", layers[LAYER_DEPENDENCIES].Id)
+		used[layers[LAYER_DEPENDENCIES].Id] = true
+	}
+
+	layout.Layers = list.Filter(LAYERS, func(layer *Layer) bool {
+		return used[layer.Id]
+	})
+}
+
+// cellCategories returns the category of every box, group and icon that is drawn for a resource
+func cellCategories(resource_map *map[string]*node.ResourceAndNode, byId map[string]*node.Node, boxes map[string]bool, overrides map[string]string) map[string]string {
+	categories := map[string]string{}
+	resources := node.SortedResources(resource_map)
+
+	categoryOf := func(typ string) string {
+		if category, ok := overrides[typ]; ok {
+			return category
+		}
+
+		return types.Category(typ)
+	}
+
+	for _, resourceAndNode := range resources {
+		categories[resourceAndNode.Node.Id()] = categoryOf(resourceAndNode.Resource.Type)
+
+		// boxes belong to the resource they are drawn for
+		if box := resourceAndNode.Node.Box; box != nil {
+			categories[box.Id()] = categoryOf(resourceAndNode.Resource.Type)
+		}
+	}
+
+	// groups belong to the resource in their center. The icons in the corners of a group have no label
+	labelled, unlabelled := list.Split(resources, func(resourceAndNode *node.ResourceAndNode) bool {
+		return resourceAndNode.Node.GetProperty("value") != ""
+	})
+
+	for _, resourceAndNode := range append(labelled, unlabelled...) {
+		group := outermostGroup(resourceAndNode.Node, byId, boxes)
+
+		if _, ok := categories[group.Id()]; !ok {
+			categories[group.Id()] = categoryOf(resourceAndNode.Resource.Type)
+		}
+	}
+
+	return categories
+}
+
+// outermostGroup returns the group a node is moved with, or the node itself if it is not in a group
+func outermostGroup(n *node.Node, byId map[string]*node.Node, boxes map[string]bool) *node.Node {
+	for {
+		parent, ok := byId[n.GetProperty("parent")]
+
+		if !ok || boxes[parent.Id()] {
+			return n
+		}
+
+		n = parent
+	}
+}
+
+func absolutePosition(n *node.Node, byId map[string]*node.Node) (int, int) {
+	geometry := n.GetGeometry()
+
+	if parent, ok := byId[n.GetProperty("parent")]; ok {
+		x, y := absolutePosition(parent, byId)
+
+		return geometry.X + x, geometry.Y + y
+	}
+
+	return geometry.X, geometry.Y
+}
@@ -3,6 +3,7 @@ package drawio
 import (
 	"cloudsketch/internal/datastructures/build_graph"
 	"cloudsketch/internal/datastructures/set"
+	"cloudsketch/internal/frontends"
 	"cloudsketch/internal/frontends/drawio/handlers/ai_services"
 	"cloudsketch/internal/frontends/drawio/handlers/api_management_api"
 	"cloudsketch/internal/frontends/drawio/handlers/api_management_service"
@@ -68,17 +69,22 @@ import (
 	"cloudsketch/internal/frontends/drawio/handlers/workspace"
 	"cloudsketch/internal/frontends/models"
 	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
 
 	"cloudsketch/internal/list"
+	"context"
 	"fmt"
+	"io"
 	"log"
+	"slices"
+	"sort"
 )
 
 type handler interface {
-	MapResource(*models.Resource) *node.Node
-	PostProcessIcon(*node.ResourceAndNode, *map[string]*node.ResourceAndNode) *node.Node
-	DrawDependencies(*models.Resource, []*models.Resource, *map[string]*node.ResourceAndNode) []*node.Arrow
-	GroupResources(*models.Resource, []*models.Resource, *map[string]*node.ResourceAndNode) []*node.Node
+	MapResource(*guid.Sequence, *models.Resource) *node.Node
+	PostProcessIcon(*guid.Sequence, *node.ResourceAndNode, *map[string]*node.ResourceAndNode) (*node.Node, error)
+	DrawDependencies(*guid.Sequence, *models.Resource, []*models.Resource, *map[string]*node.ResourceAndNode) []*node.Arrow
+	GroupResources(*guid.Sequence, *models.Resource, []*models.Resource, *map[string]*node.ResourceAndNode) ([]*node.Node, error)
 }
 
 var (
@@ -147,6 +153,11 @@ var (
 	}
 )
 
+const (
+	// name of the page when the diagram is not split into pages
+	DEFAULT_PAGE = "Page-1"
+)
+
 type drawio struct {
 }
 
@@ -154,28 +165,126 @@ func New() *drawio {
 	return &drawio{}
 }
 
-func (d *drawio) WriteDiagram(resources []*models.Resource, filename string) error {
+// Layout is the computed diagram, with positions relative to the parent of every node
+type Layout struct {
+	// boxes drawn around virtual networks, subnets and other grouping resources
+	Boxes []*node.Node
+	// invisible groups of icons that are moved together
+	Groups []*node.Node
+	Arrows []*node.Arrow
+	Icons  []*node.Node
+	// lint finding badges
+	Badges []*node.Node
+	// the layers the cells are on, if they were assigned to layers
+	Layers []*Layer
+}
+
+// Nodes returns every node in the order they are rendered, background first
+func (l *Layout) Nodes() []*node.Node {
+	return slices.Concat(l.Boxes, l.Groups, l.Icons, l.Badges)
+}
+
+func (d *drawio) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	var pages []*diagram.Page
+
+	if options.Pages == "" {
+		ids := guid.NewSequence(options.Name)
+		layout, err := computeLayout(resources, options, ids)
+
+		if err != nil {
+			return err
+		}
+
+		pages = append(pages, diagram.NewPage(ids.Next(), DEFAULT_PAGE, layout.cells()))
+	} else {
+		var err error
+		pages, err = computePages(resources, options)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	dgrm := diagram.New(pages...)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return dgrm.Write(w)
+}
+
+// cells combines everything to render in the final diagram
+func (l *Layout) cells() []string {
+	// items appended first are rendered first (in the background)
+	cellsToRender := list.Map(l.Layers, func(layer *Layer) string {
+		return layer.ToMXCell()
+	})
+	cellsToRender = append(cellsToRender, list.Map(l.Boxes, node.ToMXCell)...)
+	cellsToRender = append(cellsToRender, list.Map(l.Groups, node.ToMXCell)...)
+	cellsToRender = append(cellsToRender, list.Map(l.Arrows, func(a *node.Arrow) string {
+		return a.ToMXCell()
+	})...)
+	cellsToRender = append(cellsToRender, list.Map(l.Icons, node.ToMXCell)...)
+	cellsToRender = append(cellsToRender, list.Map(l.Badges, node.ToMXCell)...)
+
+	return cellsToRender
+}
+
+// ComputeLayout places the resources the same way they are drawn by the drawio frontend, for frontends that reuse its geometry
+func ComputeLayout(resources []*models.Resource, options *frontends.Options) (*Layout, error) {
+	return computeLayout(resources, options, guid.NewSequence(options.Name))
+}
+
+// computeLayout draws the resources with ids from the given sequence. The same sequence yields the same diagram
+func computeLayout(resources []*models.Resource, options *frontends.Options, ids *guid.Sequence) (*Layout, error) {
 	// at this point only the Azure resources are known - this function adds the corresponding DrawIO icons
-	resource_map, err := populateResourceMap(resources)
+	resource_map, err := populateResourceMap(ids, resources)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// some resources group other resources
-	groups := postProcessIcons(resource_map)
+	groups, err := postProcessIcons(ids, resource_map)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// lint findings are attached as badges before resources are moved into boxes so they move along with the resource
+	resourceFindings := findingsByResource(options.Findings, resource_map)
+	badgeGroups, badges, err := addFindingBadges(ids, resourceFindings, resource_map)
+
+	if err != nil {
+		return nil, err
+	}
+
+	groups = append(groups, badgeGroups...)
 
 	// some resources like vnets and subnets needs boxes draw around them, and their resources moved into them
-	boxes := groupResources(resource_map)
+	boxes, err := groupResources(ids, resource_map)
+
+	if err != nil {
+		return nil, err
+	}
+
+	highlightBoxes(resourceFindings, resource_map)
 
 	// with every DrawIO icon present, add the dependency arrows
-	dependencyArrows := addDependencies(resource_map)
+	dependencyArrows, err := addDependencies(ids, resource_map)
 
-	allResources := []*node.ResourceAndNode{}
-	for _, resource := range *resource_map {
-		allResources = append(allResources, resource)
+	if err != nil {
+		return nil, err
 	}
 
+	dependencyArrows = append(dependencyArrows, addCyclicDependencies(ids, resource_map)...)
+
+	// the collected data is shown when hovering a resource, and can be queried with Edit Data
+	addMetadata(resource_map)
+
+	allResources := node.SortedResources(resource_map)
+
 	// private endpoints, NICs, PIPs and NSGs are typically used as icons attached to other icons and should therefore be rendered in front of them
 	overlayResources := []string{types.PRIVATE_ENDPOINT, types.NETWORK_INTERFACE, types.PUBLIC_IP_ADDRESS, types.NETWORK_SECURITY_GROUP, types.ROUTE_TABLE}
 	allResourcesThatShouldGoInFront, allResourcesThatShouldGoInBack := list.Split(allResources, func(n *node.ResourceAndNode) bool {
@@ -189,27 +298,34 @@ func (d *drawio) WriteDiagram(resources []*models.Resource, filename string) err
 		return n.Node
 	})
 
-	// combine everything and render them in the final diagram
-	// items appended first are rendered first (in the background)
-	cellsToRender := []string{}
-	cellsToRender = append(cellsToRender, list.Map(boxes, node.ToMXCell)...)
-	cellsToRender = append(cellsToRender, list.Map(groups, node.ToMXCell)...)
-	cellsToRender = append(cellsToRender, list.Map(dependencyArrows, func(a *node.Arrow) string {
-		return a.ToMXCell()
-	})...)
-	cellsToRender = append(cellsToRender, list.Map(allResourcesNodes, node.ToMXCell)...)
+	layout := &Layout{
+		Boxes:  boxes,
+		Groups: groups,
+		Arrows: dependencyArrows,
+		Icons:  allResourcesNodes,
+		Badges: badges,
+	}
 
-	dgrm := diagram.New(cellsToRender)
+	if options.Layers {
+		assignLayers(layout, resource_map, options.Categories)
+	}
 
-	return dgrm.Write(filename)
+	return layout, nil
 }
 
-func populateResourceMap(resources []*models.Resource) (*map[string]*node.ResourceAndNode, error) {
+func populateResourceMap(ids *guid.Sequence, resources []*models.Resource) (*map[string]*node.ResourceAndNode, error) {
 	resource_map := &map[string]*node.ResourceAndNode{}
 	unhandled_resources := set.New[string]()
 
-	tasks := list.Map(resources, func(r *models.Resource) *build_graph.Task {
-		return build_graph.NewTask(r.Id, list.Map(r.DependsOn, func(m *models.Resource) string { return m.Id }), []string{}, []string{}, func() { drawResource(r, unhandled_resources, resource_map) })
+	// handlers add dependencies while drawing, which must not leak into the resources of the caller.
+	// They are drawn in id order so the layout does not depend on the order they were passed in
+	sorted := models.Clone(resources)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Id < sorted[j].Id
+	})
+
+	tasks := list.Map(sorted, func(r *models.Resource) *build_graph.Task {
+		return build_graph.NewTask(r.Id, list.Map(r.DependsOn, func(m *models.Resource) string { return m.Id }), []string{}, []string{}, func() { drawResource(ids, r, unhandled_resources, resource_map) })
 	})
 
 	bg, err := build_graph.NewGraph(tasks)
@@ -226,7 +342,7 @@ func populateResourceMap(resources []*models.Resource) (*map[string]*node.Resour
 	return resource_map, nil
 }
 
-func drawResource(resource *models.Resource, unhandled_resources *set.Set[string], resource_map *map[string]*node.ResourceAndNode) {
+func drawResource(ids *guid.Sequence, resource *models.Resource, unhandled_resources *set.Set[string], resource_map *map[string]*node.ResourceAndNode) {
 	if (*resource_map)[resource.Id] != nil {
 		// resource already drawn
 		return
@@ -246,7 +362,7 @@ func drawResource(resource *models.Resource, unhandled_resources *set.Set[string
 		return
 	}
 
-	icon := f.MapResource(resource)
+	icon := f.MapResource(ids, resource)
 
 	(*resource_map)[resource.Id] = &node.ResourceAndNode{
 		Resource: resource,
@@ -254,11 +370,20 @@ func drawResource(resource *models.Resource, unhandled_resources *set.Set[string
 	}
 }
 
-func postProcessIcons(resource_map *map[string]*node.ResourceAndNode) []*node.Node {
+func postProcessIcons(ids *guid.Sequence, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
 	nodes := []*node.Node{}
 
-	for _, resource := range *resource_map {
-		nodeToAdd := commands[resource.Resource.Type].PostProcessIcon(resource, resource_map)
+	for _, resource := range node.SortedResources(resource_map) {
+		if _, ok := (*resource_map)[resource.Resource.Id]; !ok {
+			// merged into another icon by an earlier resource
+			continue
+		}
+
+		nodeToAdd, err := commands[resource.Resource.Type].PostProcessIcon(ids, resource, resource_map)
+
+		if err != nil {
+			return nil, fmt.Errorf("error post processing %s: %+v", resource.Resource.Id, err)
+		}
 
 		if nodeToAdd == nil {
 			continue
@@ -267,19 +392,19 @@ func postProcessIcons(resource_map *map[string]*node.ResourceAndNode) []*node.No
 		nodes = append(nodes, nodeToAdd)
 	}
 
-	return nodes
+	return nodes, nil
 }
 
-func addDependencies(resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+func addDependencies(ids *guid.Sequence, resource_map *map[string]*node.ResourceAndNode) ([]*node.Arrow, error) {
 	var arrows []*node.Arrow
 
-	for _, resourceAndNode := range *resource_map {
+	for _, resourceAndNode := range node.SortedResources(resource_map) {
 		resource := resourceAndNode.Resource
 
 		f, ok := commands[resource.Type]
 
 		if !ok {
-			log.Fatalf("type %s has not been registered for rendering", resource.Type)
+			return nil, fmt.Errorf("type %s has not been registered for rendering", resource.Type)
 		}
 
 		dependencyIds := list.Filter(resource.DependsOn, func(dependency *models.Resource) bool {
@@ -296,48 +421,83 @@ func addDependencies(resource_map *map[string]*node.ResourceAndNode) []*node.Arr
 			return (*resource_map)[dependency.Id].Resource
 		})
 
-		arrowsToAdd := f.DrawDependencies(resource, resources, resource_map)
+		arrowsToAdd := f.DrawDependencies(ids, resource, resources, resource_map)
 
 		arrows = append(arrows, arrowsToAdd...)
 	}
 
-	return arrows
+	return arrows, nil
 }
 
-func groupResources(resource_map *map[string]*node.ResourceAndNode) []*node.Node {
-	resources := []*models.Resource{}
+func addCyclicDependencies(ids *guid.Sequence, resource_map *map[string]*node.ResourceAndNode) []*node.Arrow {
+	// dependencies that were removed to break a cycle are drawn dashed
+	style := "dashed=1"
+	arrows := []*node.Arrow{}
+
+	for _, resourceAndNode := range node.SortedResources(resource_map) {
+		if resourceAndNode.Node == nil {
+			continue
+		}
+
+		for _, dependency := range resourceAndNode.Resource.CyclicDependsOn {
+			target, ok := (*resource_map)[dependency.Id]
+
+			if !ok || target.Node == nil {
+				log.Printf("target %s was not drawn", dependency.Id)
+				continue
+			}
 
-	for _, resourceAndNode := range *resource_map {
-		resources = append(resources, resourceAndNode.Resource)
+			arrows = append(arrows, node.NewArrow(ids, resourceAndNode.Node.Id(), target.Node.Id(), &style))
+		}
 	}
 
+	return arrows
+}
+
+func groupResources(ids *guid.Sequence, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
+	resources := list.Map(node.SortedResources(resource_map), func(resourceAndNode *node.ResourceAndNode) *models.Resource {
+		return resourceAndNode.Resource
+	})
+
 	resourcesWithoutVnetsAndSubnets := list.Filter(resources, func(resource *models.Resource) bool {
 		return resource.Type != types.SUBNET && resource.Type != types.VIRTUAL_NETWORK && resource.Type != types.SUBSCRIPTION
 	})
 
-	boxes := list.FlatMap(resourcesWithoutVnetsAndSubnets, func(resource *models.Resource) []*node.Node {
-		return commands[resource.Type].GroupResources(resource, resources, resource_map)
-	})
+	boxes, err := drawGroups(ids, resourcesWithoutVnetsAndSubnets, resources, resource_map)
+
+	if err != nil {
+		return nil, err
+	}
 
 	// virtual netwoks, subnets and subscription needs to be handled last since they "depend" on all other resources
-	subnets := drawGroupForResourceType(resources, types.SUBNET, resource_map)
-	vnets := drawGroupForResourceType(resources, types.VIRTUAL_NETWORK, resource_map)
-	subscriptions := drawGroupForResourceType(resources, types.SUBSCRIPTION, resource_map)
+	nodes := boxes
 
-	// return subscriptions first so they are rendered in the background
-	nodes := append(subscriptions, append(vnets, append(subnets, boxes...)...)...)
+	for _, typ := range []string{types.SUBNET, types.VIRTUAL_NETWORK, types.SUBSCRIPTION} {
+		groups, err := drawGroups(ids, list.Filter(resources, func(r *models.Resource) bool { return r.Type == typ }), resources, resource_map)
 
-	return nodes
+		if err != nil {
+			return nil, err
+		}
+
+		// subscriptions end up first so they are rendered in the background
+		nodes = append(groups, nodes...)
+	}
+
+	return nodes, nil
 }
 
-func drawGroupForResourceType(resources []*models.Resource, typ string, resource_map *map[string]*node.ResourceAndNode) []*node.Node {
-	resourcesWithType := list.Filter(resources, func(r *models.Resource) bool {
-		return r.Type == typ
-	})
+func drawGroups(ids *guid.Sequence, resourcesToGroup, resources []*models.Resource, resource_map *map[string]*node.ResourceAndNode) ([]*node.Node, error) {
+	nodes := []*node.Node{}
 
-	nodes := list.FlatMap(resourcesWithType, func(resource *models.Resource) []*node.Node {
-		return commands[typ].GroupResources(resource, resources, resource_map)
-	})
+	for _, resource := range resourcesToGroup {
+		groups, err := commands[resource.Type].GroupResources(ids, resource, resources, resource_map)
+
+		if err != nil {
+			return nil, fmt.Errorf("error grouping the resources of %s: %+v", resource.Id, err)
+		}
+
+		nodes = append(nodes, groups...)
+	}
 
-	return nodes
+	return nodes, nil
 }
@@ -0,0 +1,57 @@
+package drawio
+
+import (
+	"cloudsketch/internal/frontends/drawio/handlers/node"
+	"html"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// attributes set for every resource, or used by drawio itself. Properties with these names are prefixed
+	RESERVED_ATTRIBUTES = []string{"id", "label", "link", "name", "placeholders", "resourceGroup", "resourceId", "tooltip", "type"}
+
+	invalidAttributeChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+)
+
+// addMetadata attaches the id, type, resource group and properties of every resource to its icon
+func addMetadata(resource_map *map[string]*node.ResourceAndNode) {
+	for _, resourceAndNode := range node.SortedResources(resource_map) {
+		resource := resourceAndNode.Resource
+		n := resourceAndNode.Node
+
+		n.SetName(resource.Name)
+		n.SetMetadata("resourceId", html.EscapeString(resource.Id))
+		n.SetMetadata("type", html.EscapeString(resource.Type))
+
+		if resourceGroup := resource.GetResourceGroup(); resourceGroup != "" {
+			n.SetMetadata("resourceGroup", html.EscapeString(resourceGroup))
+		}
+
+		for _, property := range slices.Sorted(maps.Keys(resource.Properties)) {
+			if property == "link" {
+				// already the link of the cell
+				continue
+			}
+
+			n.SetMetadata(attributeName(property), html.EscapeString(strings.Join(resource.Properties[property], ", ")))
+		}
+	}
+}
+
+// attributeName turns a property into a valid XML attribute name that does not collide with the other attributes
+func attributeName(property string) string {
+	name := invalidAttributeChars.ReplaceAllString(property, "_")
+
+	if slices.Contains(RESERVED_ATTRIBUTES, name) || name == "" || !isLetter(name[0]) {
+		return "property_" + name
+	}
+
+	return name
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
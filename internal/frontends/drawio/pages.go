@@ -0,0 +1,264 @@
+package drawio
+
+import (
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/containment"
+	"cloudsketch/internal/frontends/drawio/handlers/diagram"
+	"cloudsketch/internal/frontends/drawio/handlers/node"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/guid"
+	"cloudsketch/internal/list"
+	"cmp"
+	"fmt"
+	"html"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	OVERVIEW_PAGE = "Overview"
+	// page of the resources that could not be placed on a single page
+	OTHER_PAGE = "Other resources"
+
+	OVERVIEW_COLUMNS    = 4
+	OVERVIEW_BOX_WIDTH  = 240
+	OVERVIEW_BOX_HEIGHT = 100
+	OVERVIEW_SPACING    = 80
+	OVERVIEW_BOX_STYLE  = "rounded=1;fillColor=#dae8fc;strokeColor=#6c8ebf;fontStyle=1"
+)
+
+type page struct {
+	id   string
+	key  string
+	name string
+	// resources on the page, without the containers drawn around them
+	members []*models.Resource
+}
+
+// computePages draws an overview page linking to a page per subscription, virtual network or resource group
+func computePages(resources []*models.Resource, options *frontends.Options) ([]*diagram.Page, error) {
+	tree := containment.New(resources)
+	pages, pageOf := splitIntoPages(tree, options.Pages)
+
+	ids := guid.NewSequence(options.Name)
+	overviewId := ids.Next()
+
+	for _, p := range pages {
+		p.id = ids.Next()
+	}
+
+	result := []*diagram.Page{diagram.NewPage(overviewId, OVERVIEW_PAGE, overviewCells(ids, pages, pageOf))}
+
+	for _, p := range pages {
+		// every page gets its own id sequence so adding a page does not change the others
+		layout, err := computeLayout(p.resources(tree), options, guid.NewSequence(fmt.Sprintf("%s/%s", options.Name, p.key)))
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, diagram.NewPage(p.id, p.name, layout.cells()))
+	}
+
+	return result, nil
+}
+
+// splitIntoPages returns the pages sorted by name, and the page of every resource that is not only drawn for context
+func splitIntoPages(tree *containment.Tree, pagesBy string) ([]*page, map[string]*page) {
+	keys := map[string]string{}
+	names := map[string]string{}
+
+	for _, resource := range tree.Resources() {
+		if key, name := pageKey(tree, resource, pagesBy); name != "" {
+			keys[resource.Id] = key
+			names[key] = name
+		}
+	}
+
+	placeNeighbours(tree, keys)
+
+	pagesByKey := map[string]*page{}
+	pageOf := map[string]*page{}
+
+	for _, resource := range tree.Resources() {
+		key, ok := keys[resource.Id]
+
+		if !ok {
+			// subscriptions, virtual networks and subnets are drawn on the pages of the resources in them
+			if slices.Contains(containment.CONTAINERS, resource.Type) {
+				continue
+			}
+
+			key = ""
+			names[key] = OTHER_PAGE
+		}
+
+		p, ok := pagesByKey[key]
+
+		if !ok {
+			p = &page{key: key, name: names[key]}
+			pagesByKey[key] = p
+		}
+
+		p.members = append(p.members, resource)
+		pageOf[resource.Id] = p
+	}
+
+	pages := slices.SortedFunc(maps.Values(pagesByKey), func(a, b *page) int {
+		// the remaining resources come last
+		if (a.key == "") != (b.key == "") {
+			return strings.Compare(b.key, a.key)
+		}
+
+		return cmp.Or(
+			strings.Compare(strings.ToLower(a.name), strings.ToLower(b.name)),
+			strings.Compare(a.key, b.key),
+		)
+	})
+
+	return pages, pageOf
+}
+
+// pageKey returns the page a resource belongs on, or an empty name if it is not known from the resource itself
+func pageKey(tree *containment.Tree, resource *models.Resource, pagesBy string) (string, string) {
+	container := ""
+
+	switch pagesBy {
+	case frontends.PAGES_RESOURCE_GROUP:
+		resourceGroup := resource.GetResourceGroup()
+
+		if resourceGroup == "" {
+			return "", ""
+		}
+
+		return strings.ToLower(resourceGroup), resourceGroup
+	case frontends.PAGES_SUBSCRIPTION:
+		container = types.SUBSCRIPTION
+	case frontends.PAGES_VIRTUAL_NETWORK:
+		container = types.VIRTUAL_NETWORK
+	}
+
+	for r := resource; r != nil; r = tree.Parent(r) {
+		if r.Type == container {
+			return r.Id, r.Name
+		}
+	}
+
+	return "", ""
+}
+
+// placeNeighbours puts resources outside of a container, like public IPs and network security groups, on the page of the resources they are connected to.
+// Resources connected to several pages are left out
+func placeNeighbours(tree *containment.Tree, keys map[string]string) {
+	neighbours := map[string][]*models.Resource{}
+
+	for _, resource := range tree.Resources() {
+		for _, dependency := range resource.DependsOn {
+			neighbours[resource.Id] = append(neighbours[resource.Id], dependency)
+			neighbours[dependency.Id] = append(neighbours[dependency.Id], resource)
+		}
+	}
+
+	for placed := true; placed; {
+		placed = false
+
+		for _, resource := range tree.Resources() {
+			if _, ok := keys[resource.Id]; ok || slices.Contains(containment.CONTAINERS, resource.Type) {
+				continue
+			}
+
+			candidates := map[string]bool{}
+
+			for _, neighbour := range neighbours[resource.Id] {
+				if key, ok := keys[neighbour.Id]; ok && !slices.Contains(containment.CONTAINERS, neighbour.Type) {
+					candidates[key] = true
+				}
+			}
+
+			if len(candidates) != 1 {
+				continue
+			}
+
+			for key := range candidates {
+				keys[resource.Id] = key
+			}
+
+			placed = true
+		}
+	}
+}
+
+// resources returns the members of the page and the containers around them. Dependencies on resources of other pages are dropped, the overview connects the pages instead
+func (p *page) resources(tree *containment.Tree) []*models.Resource {
+	included := map[string]*models.Resource{}
+
+	for _, member := range p.members {
+		for r := member; r != nil; r = tree.Parent(r) {
+			clone := *r
+			included[r.Id] = &clone
+		}
+	}
+
+	onPage := func(r *models.Resource) bool {
+		_, ok := included[r.Id]
+		return ok
+	}
+
+	toCopy := func(r *models.Resource) *models.Resource {
+		return included[r.Id]
+	}
+
+	for _, r := range included {
+		r.DependsOn = list.Map(list.Filter(r.DependsOn, onPage), toCopy)
+		r.CyclicDependsOn = list.Map(list.Filter(r.CyclicDependsOn, onPage), toCopy)
+	}
+
+	return slices.Collect(maps.Values(included))
+}
+
+// overviewCells draws a box per page linking to it, with arrows between pages that have dependencies on each other
+func overviewCells(ids *guid.Sequence, pages []*page, pageOf map[string]*page) []string {
+	boxes := map[string]*node.Node{}
+	cells := []string{}
+
+	for i, p := range pages {
+		geometry := &node.Geometry{
+			X:      (i % OVERVIEW_COLUMNS) * (OVERVIEW_BOX_WIDTH + OVERVIEW_SPACING),
+			Y:      (i / OVERVIEW_COLUMNS) * (OVERVIEW_BOX_HEIGHT + OVERVIEW_SPACING),
+			Width:  OVERVIEW_BOX_WIDTH,
+			Height: OVERVIEW_BOX_HEIGHT,
+		}
+
+		style := OVERVIEW_BOX_STYLE
+		box := node.NewBox(ids, geometry, &style)
+		// the label is html, the line break must survive escaping of the name
+		box.SetProperty("value", fmt.Sprintf("%s<br>%v resources", html.EscapeString(p.name), len(p.members)))
+		box.SetProperty("link", fmt.Sprintf("data:page/id,%s", p.id))
+
+		boxes[p.key] = box
+		cells = append(cells, box.ToMXCell())
+	}
+
+	dependencies := map[[2]string]bool{}
+
+	for _, p := range pages {
+		for _, member := range p.members {
+			for _, dependency := range member.DependsOn {
+				if target, ok := pageOf[dependency.Id]; ok && target != p {
+					dependencies[[2]string{p.key, target.key}] = true
+				}
+			}
+		}
+	}
+
+	for _, dependency := range slices.SortedFunc(maps.Keys(dependencies), func(a, b [2]string) int {
+		return cmp.Or(strings.Compare(a[0], b[0]), strings.Compare(a[1], b[1]))
+	}) {
+		arrow := node.NewArrow(ids, boxes[dependency[0]].Id(), boxes[dependency[1]].Id(), nil)
+		cells = append(cells, arrow.ToMXCell())
+	}
+
+	return cells
+}
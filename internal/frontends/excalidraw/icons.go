@@ -0,0 +1,90 @@
+package excalidraw
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+const (
+	MIME_TYPE_SVG = "image/svg+xml"
+
+	PLACEHOLDER_FILL   = "#dae8fc"
+	PLACEHOLDER_STROKE = "#6c8ebf"
+)
+
+// icons embeds the drawio icons into the scene, since excalidraw cannot reference images by URL
+type icons struct {
+	directory string
+	files     map[string]*File
+}
+
+// newIcons reads icons from iconBase when it is a local directory. Remote icon bases cannot be fetched while rendering, so placeholders are used instead
+func newIcons(iconBase string) *icons {
+	directory := ""
+
+	if iconBase != "" && !strings.HasPrefix(iconBase, "http://") && !strings.HasPrefix(iconBase, "https://") {
+		directory = iconBase
+	}
+
+	return &icons{
+		directory: directory,
+		files:     map[string]*File{},
+	}
+}
+
+func (i *icons) file(image string) *File {
+	if file, ok := i.files[image]; ok {
+		return file
+	}
+
+	content, err := i.read(image)
+
+	if err != nil {
+		content = placeholder(image)
+	}
+
+	dataURL := fmt.Sprintf("data:%s;base64,%s", MIME_TYPE_SVG, base64.StdEncoding.EncodeToString(content))
+	hash := sha1.Sum([]byte(dataURL))
+
+	file := &File{
+		MimeType: MIME_TYPE_SVG,
+		Id:       hex.EncodeToString(hash[:]),
+		DataURL:  dataURL,
+		Created:  1,
+	}
+
+	i.files[image] = file
+
+	return file
+}
+
+func (i *icons) read(image string) ([]byte, error) {
+	if i.directory == "" || image == "" {
+		return nil, os.ErrNotExist
+	}
+
+	return os.ReadFile(filepath.Join(i.directory, filepath.FromSlash(image)))
+}
+
+// placeholder draws a rounded square with the initials of the icon, e.g. VM for img/lib/azure2/compute/Virtual_Machine.svg
+func placeholder(image string) []byte {
+	name := strings.TrimSuffix(path.Base(image), path.Ext(image))
+	initials := ""
+
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		initials += string(unicode.ToUpper([]rune(word)[0]))
+	}
+
+	if len(initials) > 3 {
+		initials = initials[:3]
+	}
+
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="64" height="64" viewBox="0 0 64 64"><rect x="2" y="2" width="60" height="60" rx="8" fill="%s" stroke="%s" stroke-width="2"/><text x="32" y="38" font-family="Helvetica, Arial, sans-serif" font-size="18" text-anchor="middle" fill="#1e1e1e">%s</text></svg>`, PLACEHOLDER_FILL, PLACEHOLDER_STROKE, initials))
+}
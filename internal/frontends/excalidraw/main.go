@@ -0,0 +1,388 @@
+package excalidraw
+
+import (
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/drawio"
+	"cloudsketch/internal/frontends/drawio/handlers/node"
+	"cloudsketch/internal/frontends/models"
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	SOURCE = "https://github.com/fremartini/cloudsketch"
+
+	FONT_SIZE = 12
+	// excalidraw has no text measuring outside the browser, so label widths are estimated
+	CHARACTER_WIDTH = 7
+	LABEL_MARGIN    = 4
+
+	DEFAULT_STROKE = "#1e1e1e"
+)
+
+type Scene struct {
+	Type     string           `json:"type"`
+	Version  int              `json:"version"`
+	Source   string           `json:"source"`
+	Elements []*Element       `json:"elements"`
+	AppState AppState         `json:"appState"`
+	Files    map[string]*File `json:"files"`
+}
+
+type AppState struct {
+	ViewBackgroundColor string `json:"viewBackgroundColor"`
+}
+
+type File struct {
+	MimeType string `json:"mimeType"`
+	Id       string `json:"id"`
+	DataURL  string `json:"dataURL"`
+	Created  int64  `json:"created"`
+}
+
+type Element struct {
+	Id              string          `json:"id"`
+	Type            string          `json:"type"`
+	X               int             `json:"x"`
+	Y               int             `json:"y"`
+	Width           int             `json:"width"`
+	Height          int             `json:"height"`
+	Angle           int             `json:"angle"`
+	StrokeColor     string          `json:"strokeColor"`
+	BackgroundColor string          `json:"backgroundColor"`
+	FillStyle       string          `json:"fillStyle"`
+	StrokeWidth     int             `json:"strokeWidth"`
+	StrokeStyle     string          `json:"strokeStyle"`
+	Roughness       int             `json:"roughness"`
+	Opacity         int             `json:"opacity"`
+	GroupIds        []string        `json:"groupIds"`
+	Seed            uint32          `json:"seed"`
+	Version         int             `json:"version"`
+	VersionNonce    uint32          `json:"versionNonce"`
+	IsDeleted       bool            `json:"isDeleted"`
+	BoundElements   []*BoundElement `json:"boundElements"`
+	Updated         int64           `json:"updated"`
+	Link            *string         `json:"link"`
+	Locked          bool            `json:"locked"`
+
+	// text
+	Text          string  `json:"text,omitempty"`
+	OriginalText  string  `json:"originalText,omitempty"`
+	FontSize      int     `json:"fontSize,omitempty"`
+	FontFamily    int     `json:"fontFamily,omitempty"`
+	TextAlign     string  `json:"textAlign,omitempty"`
+	VerticalAlign string  `json:"verticalAlign,omitempty"`
+	ContainerId   *string `json:"containerId,omitempty"`
+	LineHeight    float64 `json:"lineHeight,omitempty"`
+
+	// image
+	FileId string `json:"fileId,omitempty"`
+	Status string `json:"status,omitempty"`
+	Scale  []int  `json:"scale,omitempty"`
+
+	// arrow
+	Points       [][2]int `json:"points,omitempty"`
+	StartBinding *Binding `json:"startBinding,omitempty"`
+	EndBinding   *Binding `json:"endBinding,omitempty"`
+	EndArrowhead string   `json:"endArrowhead,omitempty"`
+}
+
+type BoundElement struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type Binding struct {
+	ElementId string `json:"elementId"`
+	Focus     int    `json:"focus"`
+	Gap       int    `json:"gap"`
+}
+
+type excalidraw struct {
+}
+
+func New() *excalidraw {
+	return &excalidraw{}
+}
+
+func (e *excalidraw) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	// the geometry is the same as the drawio diagram
+	layout, err := drawio.ComputeLayout(resources, options)
+
+	if err != nil {
+		return err
+	}
+
+	scene := ToScene(layout, newIcons(options.IconBase))
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(scene)
+}
+
+type converter struct {
+	scene    *Scene
+	icons    *icons
+	byId     map[string]*node.Node
+	elements map[string]*Element
+}
+
+func ToScene(layout *drawio.Layout, icons *icons) *Scene {
+	c := &converter{
+		scene: &Scene{
+			Type:     "excalidraw",
+			Version:  2,
+			Source:   SOURCE,
+			Elements: []*Element{},
+			AppState: AppState{ViewBackgroundColor: "#ffffff"},
+			Files:    map[string]*File{},
+		},
+		icons:    icons,
+		byId:     map[string]*node.Node{},
+		elements: map[string]*Element{},
+	}
+
+	for _, n := range layout.Nodes() {
+		c.byId[n.Id()] = n
+	}
+
+	// same order as the drawio diagram, so boxes are in the background
+	for _, n := range layout.Boxes {
+		c.addShape(n, "rectangle")
+	}
+
+	for _, n := range layout.Icons {
+		c.addIcon(n)
+	}
+
+	for _, n := range layout.Badges {
+		c.addShape(n, "diamond")
+	}
+
+	for _, arrow := range layout.Arrows {
+		c.addArrow(arrow)
+	}
+
+	return c.scene
+}
+
+func (c *converter) addShape(n *node.Node, typ string) {
+	style := parseStyle(n.GetProperty("style"))
+	x, y := c.position(n)
+	geometry := n.GetGeometry()
+
+	shape := newElement(n.Id(), typ, x, y, geometry.Width, geometry.Height)
+	shape.GroupIds = c.groups(n)
+	shape.StrokeColor = styleOrDefault(style, "strokeColor", DEFAULT_STROKE)
+	shape.BackgroundColor = styleOrDefault(style, "fillColor", "transparent")
+	shape.StrokeWidth = intOrDefault(style, "strokeWidth", 1)
+	shape.Opacity = intOrDefault(style, "opacity", 100)
+
+	c.add(shape)
+
+	if label := n.GetProperty("value"); label != "" {
+		text := newText(n.Id()+"-label", label, x, y, geometry.Width, geometry.Height)
+		text.ContainerId = &shape.Id
+		text.GroupIds = shape.GroupIds
+		text.StrokeColor = styleOrDefault(style, "fontColor", DEFAULT_STROKE)
+		text.VerticalAlign = "middle"
+
+		shape.BoundElements = append(shape.BoundElements, &BoundElement{Id: text.Id, Type: "text"})
+
+		c.add(text)
+	}
+}
+
+func (c *converter) addIcon(n *node.Node) {
+	style := parseStyle(n.GetProperty("style"))
+	x, y := c.position(n)
+	geometry := n.GetGeometry()
+
+	file := c.icons.file(style["image"])
+	c.scene.Files[file.Id] = file
+
+	// the image and its label move together
+	groupIds := append([]string{n.Id() + "-group"}, c.groups(n)...)
+
+	image := newElement(n.Id(), "image", x, y, geometry.Width, geometry.Height)
+	image.GroupIds = groupIds
+	image.FileId = file.Id
+	image.Status = "saved"
+	image.Scale = []int{1, 1}
+
+	if link := n.GetProperty("link"); link != "" {
+		image.Link = &link
+	}
+
+	c.add(image)
+
+	label := n.GetProperty("value")
+
+	if label == "" {
+		return
+	}
+
+	// drawio draws labels centered below the icon
+	width := len(label) * CHARACTER_WIDTH
+	text := newText(n.Id()+"-label", label, x+geometry.Width/2-width/2, y+geometry.Height+LABEL_MARGIN, width, FONT_SIZE*2)
+	text.GroupIds = groupIds
+
+	c.add(text)
+}
+
+func (c *converter) addArrow(arrow *node.Arrow) {
+	source, okSource := c.elements[arrow.Source()]
+	target, okTarget := c.elements[arrow.Target()]
+
+	if !okSource || !okTarget {
+		return
+	}
+
+	style := parseStyle(arrow.GetProperty("style"))
+
+	startX, startY := source.X+source.Width/2, source.Y+source.Height/2
+	endX, endY := target.X+target.Width/2, target.Y+target.Height/2
+
+	id := arrow.GetProperty("id")
+
+	element := newElement(id, "arrow", startX, startY, abs(endX-startX), abs(endY-startY))
+	element.Points = [][2]int{{0, 0}, {endX - startX, endY - startY}}
+	element.StartBinding = &Binding{ElementId: source.Id, Gap: LABEL_MARGIN}
+	element.EndBinding = &Binding{ElementId: target.Id, Gap: LABEL_MARGIN}
+	element.EndArrowhead = "arrow"
+
+	if style["dashed"] == "1" {
+		element.StrokeStyle = "dashed"
+	}
+
+	source.BoundElements = append(source.BoundElements, &BoundElement{Id: id, Type: "arrow"})
+	target.BoundElements = append(target.BoundElements, &BoundElement{Id: id, Type: "arrow"})
+
+	c.add(element)
+}
+
+func (c *converter) add(element *Element) {
+	c.elements[element.Id] = element
+	c.scene.Elements = append(c.scene.Elements, element)
+}
+
+// position returns the absolute position of a node. Drawio positions are relative to the parent cell
+func (c *converter) position(n *node.Node) (int, int) {
+	geometry := n.GetGeometry()
+	x, y := geometry.X, geometry.Y
+
+	if parent, ok := c.byId[n.GetProperty("parent")]; ok {
+		parentX, parentY := c.position(parent)
+
+		return x + parentX, y + parentY
+	}
+
+	return x, y
+}
+
+// groups returns the drawio groups a node is in, innermost first
+func (c *converter) groups(n *node.Node) []string {
+	groups := []string{}
+
+	for parent, ok := c.byId[n.GetProperty("parent")]; ok; parent, ok = c.byId[parent.GetProperty("parent")] {
+		if _, isGroup := parseStyle(parent.GetProperty("style"))["group"]; isGroup {
+			groups = append(groups, parent.Id())
+		}
+	}
+
+	return groups
+}
+
+func newElement(id, typ string, x, y, width, height int) *Element {
+	return &Element{
+		Id:              id,
+		Type:            typ,
+		X:               x,
+		Y:               y,
+		Width:           width,
+		Height:          height,
+		StrokeColor:     DEFAULT_STROKE,
+		BackgroundColor: "transparent",
+		FillStyle:       "solid",
+		StrokeWidth:     1,
+		StrokeStyle:     "solid",
+		Opacity:         100,
+		GroupIds:        []string{},
+		Seed:            seed(id),
+		Version:         1,
+		VersionNonce:    seed(id + "-nonce"),
+		Updated:         1,
+	}
+}
+
+func newText(id, text string, x, y, width, height int) *Element {
+	element := newElement(id, "text", x, y, width, height)
+	element.Text = text
+	element.OriginalText = text
+	element.FontSize = FONT_SIZE
+	element.FontFamily = 2
+	element.TextAlign = "center"
+	element.VerticalAlign = "top"
+	element.LineHeight = 1.25
+
+	return element
+}
+
+// seed derives the random seed excalidraw uses for its hand drawn look from the id, so scenes are deterministic
+func seed(id string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+
+	return h.Sum32()
+}
+
+// parseStyle turns a drawio style like rounded=0;fillColor=#dae8fc into a map. Flags without a value map to an empty string
+func parseStyle(style string) map[string]string {
+	result := map[string]string{}
+
+	for _, part := range strings.Split(style, ";") {
+		if part == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(part, "=")
+		result[key] = value
+	}
+
+	return result
+}
+
+func styleOrDefault(style map[string]string, key, fallback string) string {
+	if value, ok := style[key]; ok && value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+func intOrDefault(style map[string]string, key string, fallback int) int {
+	value, err := strconv.Atoi(style[key])
+
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
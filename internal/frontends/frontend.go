@@ -1,7 +1,33 @@
 package frontends
 
-import "cloudsketch/internal/frontends/models"
+import (
+	"cloudsketch/internal/frontends/models"
+	lintModels "cloudsketch/internal/lint/models"
+	"context"
+	"io"
+)
+
+const (
+	PAGES_SUBSCRIPTION    = "subscription"
+	PAGES_VIRTUAL_NETWORK = "vnet"
+	PAGES_RESOURCE_GROUP  = "resource-group"
+)
 
 type Frontend interface {
-	WriteDiagram(resources []*models.Resource, filename string) error
+	WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *Options) error
+}
+
+type Options struct {
+	// name of the diagram, used by formats that embed a name in their output
+	Name string
+	// lint findings to overlay on the diagram. Ignored by frontends that can not show them
+	Findings []*lintModels.Finding
+	// base URL or directory of the icons referenced by frontends that link icons instead of embedding them
+	IconBase string
+	// split the diagram into a page per subscription, virtual network or resource group. Empty draws a single page. Ignored by frontends without pages
+	Pages string
+	// put the resources on a layer per category. Ignored by frontends without layers
+	Layers bool
+	// category per resource type, overriding types.CATEGORIES
+	Categories map[string]string
 }
@@ -0,0 +1,173 @@
+package graphml
+
+import (
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/containment"
+	"cloudsketch/internal/frontends/models"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	NAMESPACE = "http://graphml.graphdrawing.org/xmlns"
+
+	KEY_LABEL          = "label"
+	KEY_TYPE           = "type"
+	KEY_RESOURCE_GROUP = "resourceGroup"
+	KEY_PARENT         = "parent"
+	KEY_KIND           = "kind"
+)
+
+type graphml struct {
+}
+
+func New() *graphml {
+	return &graphml{}
+}
+
+type document struct {
+	XMLName xml.Name `xml:"graphml"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Keys    []key    `xml:"key"`
+	Graph   graph    `xml:"graph"`
+}
+
+type key struct {
+	Id   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graph struct {
+	Id          string `xml:"id,attr"`
+	EdgeDefault string `xml:"edgedefault,attr"`
+	Nodes       []node `xml:"node"`
+	Edges       []edge `xml:"edge"`
+}
+
+type node struct {
+	Id   string `xml:"id,attr"`
+	Data []data `xml:"data"`
+}
+
+type edge struct {
+	Id     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []data `xml:"data"`
+}
+
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (g *graphml) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	content, err := ToGraphML(resources, options.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, content)
+
+	return err
+}
+
+func ToGraphML(resources []*models.Resource, name string) (string, error) {
+	tree := containment.New(resources)
+
+	// every property gets its own attribute. Keys are numbered in the order of the property names
+	propertyKeys := map[string]string{}
+
+	for i, property := range propertyNames(tree.Resources()) {
+		propertyKeys[property] = fmt.Sprintf("p%v", i)
+	}
+
+	d := document{
+		Xmlns: NAMESPACE,
+		Keys: []key{
+			{Id: KEY_LABEL, For: "node", Name: KEY_LABEL, Type: "string"},
+			{Id: KEY_TYPE, For: "node", Name: KEY_TYPE, Type: "string"},
+			{Id: KEY_RESOURCE_GROUP, For: "node", Name: KEY_RESOURCE_GROUP, Type: "string"},
+			{Id: KEY_PARENT, For: "node", Name: KEY_PARENT, Type: "string"},
+			{Id: KEY_KIND, For: "edge", Name: KEY_KIND, Type: "string"},
+		},
+		Graph: graph{
+			Id:          name,
+			EdgeDefault: "directed",
+		},
+	}
+
+	for _, property := range slices.Sorted(maps.Keys(propertyKeys)) {
+		d.Keys = append(d.Keys, key{Id: propertyKeys[property], For: "node", Name: property, Type: "string"})
+	}
+
+	for _, resource := range tree.Resources() {
+		d.Graph.Nodes = append(d.Graph.Nodes, toNode(tree, resource, propertyKeys))
+	}
+
+	for i, e := range tree.Edges() {
+		d.Graph.Edges = append(d.Graph.Edges, edge{
+			Id:     fmt.Sprintf("e%v", i),
+			Source: e.Source.Id,
+			Target: e.Target.Id,
+			Data:   []data{{Key: KEY_KIND, Value: e.Kind}},
+		})
+	}
+
+	content, err := xml.MarshalIndent(d, "", "  ")
+
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(content) + "\n", nil
+}
+
+func toNode(tree *containment.Tree, resource *models.Resource, propertyKeys map[string]string) node {
+	n := node{
+		Id: resource.Id,
+		Data: []data{
+			{Key: KEY_LABEL, Value: resource.Name},
+			{Key: KEY_TYPE, Value: resource.Type},
+		},
+	}
+
+	if resourceGroup := resource.GetResourceGroup(); resourceGroup != "" {
+		n.Data = append(n.Data, data{Key: KEY_RESOURCE_GROUP, Value: resourceGroup})
+	}
+
+	if parent := tree.Parent(resource); parent != nil {
+		n.Data = append(n.Data, data{Key: KEY_PARENT, Value: parent.Id})
+	}
+
+	// graphml attributes hold a single value, so lists are joined
+	for _, property := range slices.Sorted(maps.Keys(resource.Properties)) {
+		n.Data = append(n.Data, data{Key: propertyKeys[property], Value: strings.Join(resource.Properties[property], ", ")})
+	}
+
+	return n
+}
+
+func propertyNames(resources []*models.Resource) []string {
+	names := map[string]bool{}
+
+	for _, resource := range resources {
+		for property := range resource.Properties {
+			names[property] = true
+		}
+	}
+
+	return slices.Sorted(maps.Keys(names))
+}
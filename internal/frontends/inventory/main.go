@@ -0,0 +1,265 @@
+package inventory
+
+import (
+	"bytes"
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/containment"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/list"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	FORMAT_MARKDOWN = "markdown"
+	FORMAT_CSV      = "csv"
+
+	// shown in the Markdown tables for empty cells
+	NONE = "-"
+)
+
+var (
+	COLUMNS = []string{"Subscription", "Resource group", "Type", "Name", "Virtual network", "Subnet", "IP addresses", "Private endpoints", "Dependencies", "Dependents", "Link"}
+)
+
+type inventory struct {
+	format string
+}
+
+func New(format string) *inventory {
+	return &inventory{
+		format: format,
+	}
+}
+
+type Row struct {
+	Subscription     string
+	ResourceGroup    string
+	Type             string
+	Name             string
+	VirtualNetwork   string
+	Subnet           string
+	IPAddresses      []string
+	PrivateEndpoints []string
+	Dependencies     int
+	Dependents       int
+	Link             string
+}
+
+func (i *inventory) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	rows := Rows(resources)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if i.format == FORMAT_CSV {
+		return writeCSV(w, rows)
+	}
+
+	_, err := io.WriteString(w, ToMarkdown(rows))
+
+	return err
+}
+
+// Rows returns a row per resource, sorted by subscription, resource group, type and name
+func Rows(resources []*models.Resource) []*Row {
+	tree := containment.New(resources)
+
+	subscriptions := map[string]string{}
+	dependents := map[string]int{}
+	ipAddresses := map[string][]string{}
+	privateEndpoints := map[string][]string{}
+
+	for _, resource := range tree.Resources() {
+		if resource.Type == types.SUBSCRIPTION {
+			subscriptions[strings.ToLower(resource.Id)] = resource.Name
+		}
+
+		for _, dependency := range resource.DependsOn {
+			dependents[dependency.Id]++
+		}
+
+		// network interfaces and private endpoints point to the resource they are attached to
+		attachedTo, ok := resource.Properties["attachedTo"]
+
+		if !ok {
+			continue
+		}
+
+		switch resource.Type {
+		case types.NETWORK_INTERFACE:
+			ipAddresses[attachedTo[0]] = append(ipAddresses[attachedTo[0]], resource.Properties["ip"]...)
+		case types.PRIVATE_ENDPOINT:
+			privateEndpoints[attachedTo[0]] = append(privateEndpoints[attachedTo[0]], resource.Name)
+		}
+	}
+
+	rows := list.Map(tree.Resources(), func(resource *models.Resource) *Row {
+		row := &Row{
+			Subscription:     subscriptionOf(resource, subscriptions),
+			ResourceGroup:    resource.GetResourceGroup(),
+			Type:             types.Readable(resource.Type),
+			Name:             resource.Name,
+			IPAddresses:      slices.Concat(resource.Properties["ip"], ipAddresses[resource.Id]),
+			PrivateEndpoints: privateEndpoints[resource.Id],
+			Dependencies:     len(resource.DependsOn),
+			Dependents:       dependents[resource.Id],
+		}
+
+		for parent := tree.Parent(resource); parent != nil; parent = tree.Parent(parent) {
+			switch parent.Type {
+			case types.VIRTUAL_NETWORK:
+				row.VirtualNetwork = parent.Name
+			case types.SUBNET:
+				row.Subnet = parent.Name
+			}
+		}
+
+		if link := resource.GetLinkOrDefault(); link != nil {
+			row.Link = *link
+		}
+
+		return row
+	})
+
+	slices.SortStableFunc(rows, func(a, b *Row) int {
+		return cmp.Or(
+			strings.Compare(a.Subscription, b.Subscription),
+			strings.Compare(a.ResourceGroup, b.ResourceGroup),
+			strings.Compare(a.Type, b.Type),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+
+	return rows
+}
+
+// subscriptionOf returns the name of the subscription in the id of the resource, or the subscription id if it was not fetched
+func subscriptionOf(resource *models.Resource, subscriptions map[string]string) string {
+	// /subscriptions/<id>/...
+	segments := strings.Split(resource.Id, "/")
+
+	if len(segments) < 3 || strings.ToLower(segments[1]) != "subscriptions" {
+		return ""
+	}
+
+	id := strings.ToLower(strings.Join(segments[:3], "/"))
+
+	if name, ok := subscriptions[id]; ok {
+		return name
+	}
+
+	return segments[2]
+}
+
+func ToMarkdown(rows []*Row) string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("# Inventory\n")
+
+	if len(rows) == 0 {
+		buffer.WriteString("\nNo resources found.\n")
+		return buffer.String()
+	}
+
+	writeSummary(&buffer, rows)
+
+	// the subscription and resource group are in the headings
+	columns := COLUMNS[2:]
+
+	subscription, resourceGroup := "", ""
+
+	for i, row := range rows {
+		if i == 0 || row.Subscription != subscription {
+			subscription = row.Subscription
+			buffer.WriteString(fmt.Sprintf("\n## %s\n", orNone(subscription)))
+			resourceGroup = "\x00"
+		}
+
+		if row.ResourceGroup != resourceGroup {
+			resourceGroup = row.ResourceGroup
+			buffer.WriteString(fmt.Sprintf("\n### %s\n\n", orNone(resourceGroup)))
+			buffer.WriteString(fmt.Sprintf("| %s |\n", strings.Join(columns, " | ")))
+			buffer.WriteString(fmt.Sprintf("|%s\n", strings.Repeat(" --- |", len(columns))))
+		}
+
+		cells := list.Map(fields(row)[2:], func(cell string) string {
+			return strings.ReplaceAll(orNone(cell), "|", `\|`)
+		})
+
+		if row.Link != "" {
+			cells[len(cells)-1] = fmt.Sprintf("[portal](%s)", row.Link)
+		}
+
+		buffer.WriteString(fmt.Sprintf("| %s |\n", strings.Join(cells, " | ")))
+	}
+
+	return buffer.String()
+}
+
+func writeSummary(buffer *bytes.Buffer, rows []*Row) {
+	counts := map[string]int{}
+
+	for _, row := range rows {
+		counts[row.Type]++
+	}
+
+	buffer.WriteString(fmt.Sprintf("\n%v resources\n\n", len(rows)))
+	buffer.WriteString("| Type | Count |\n")
+	buffer.WriteString("| --- | --- |\n")
+
+	for _, typ := range slices.Sorted(maps.Keys(counts)) {
+		buffer.WriteString(fmt.Sprintf("| %s | %v |\n", typ, counts[typ]))
+	}
+}
+
+func writeCSV(w io.Writer, rows []*Row) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(COLUMNS); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := writer.Write(fields(row)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// fields returns the cells of a row in the order of COLUMNS
+func fields(row *Row) []string {
+	return []string{
+		row.Subscription,
+		row.ResourceGroup,
+		row.Type,
+		row.Name,
+		row.VirtualNetwork,
+		row.Subnet,
+		strings.Join(row.IPAddresses, ", "),
+		strings.Join(row.PrivateEndpoints, ", "),
+		fmt.Sprint(row.Dependencies),
+		fmt.Sprint(row.Dependents),
+		row.Link,
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return NONE
+	}
+
+	return s
+}
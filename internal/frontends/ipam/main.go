@@ -0,0 +1,186 @@
+package ipam
+
+import (
+	"bytes"
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/models"
+	addressSpace "cloudsketch/internal/ipam"
+	"cloudsketch/internal/list"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"net/netip"
+	"strings"
+)
+
+const (
+	// the usage bar of an address range has 2^BAR_BITS cells
+	BAR_BITS = 6
+
+	USED    = "█"
+	PARTIAL = "▒"
+	FREE    = "░"
+)
+
+type ipam struct {
+}
+
+func New() *ipam {
+	return &ipam{}
+}
+
+func (i *ipam) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	content := ToMarkdown(addressSpace.Plan(resources), addressSpace.Overlaps(resources))
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, content)
+
+	return err
+}
+
+func ToMarkdown(plan []*addressSpace.VirtualNetwork, overlaps []*addressSpace.Overlap) string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("# IP address plan\n")
+
+	if len(plan) == 0 {
+		buffer.WriteString("\nNo virtual networks found.\n")
+	}
+
+	for _, vnet := range plan {
+		writeVirtualNetwork(&buffer, vnet)
+	}
+
+	writeOverlaps(&buffer, overlaps)
+
+	return buffer.String()
+}
+
+func writeVirtualNetwork(buffer *bytes.Buffer, vnet *addressSpace.VirtualNetwork) {
+	buffer.WriteString(fmt.Sprintf("\n## %s\n\n", vnet.Resource.Name))
+
+	if resourceGroup := vnet.Resource.GetResourceGroup(); resourceGroup != "" {
+		buffer.WriteString(fmt.Sprintf("Resource group: %s\n\n", resourceGroup))
+	}
+
+	if len(vnet.Prefixes) == 0 {
+		// snapshots taken by older versions only contain the prefix length
+		buffer.WriteString("Address space unknown. Refresh the snapshot to capture address prefixes.\n")
+		return
+	}
+
+	size := addressSpace.Size(vnet.Prefixes)
+	used := addressSpace.Size(vnet.Used())
+
+	buffer.WriteString(fmt.Sprintf("Address space: %s (%s addresses, %s used, %s free)\n\n", joinPrefixes(vnet.Prefixes), size, used, new(big.Int).Sub(size, used)))
+
+	buffer.WriteString("```\n")
+
+	for _, prefix := range vnet.Prefixes {
+		buffer.WriteString(fmt.Sprintf("%-18s %s\n", prefix, bar(prefix, vnet.Used())))
+	}
+
+	buffer.WriteString("```\n\n")
+
+	if len(vnet.Subnets) > 0 {
+		buffer.WriteString("| Subnet | Address prefixes | Addresses |\n")
+		buffer.WriteString("| --- | --- | --- |\n")
+
+		for _, subnet := range vnet.Subnets {
+			prefixes := "unknown"
+
+			if len(subnet.Prefixes) > 0 {
+				prefixes = joinPrefixes(subnet.Prefixes)
+			}
+
+			buffer.WriteString(fmt.Sprintf("| %s | %s | %s |\n", subnet.Resource.Name, prefixes, addressSpace.Size(subnet.Prefixes)))
+		}
+
+		buffer.WriteString("\n")
+	}
+
+	free := vnet.Free()
+
+	if len(free) == 0 {
+		buffer.WriteString("Free ranges: none\n")
+		return
+	}
+
+	buffer.WriteString(fmt.Sprintf("Free ranges: %s\n", joinPrefixes(free)))
+}
+
+func writeOverlaps(buffer *bytes.Buffer, overlaps []*addressSpace.Overlap) {
+	buffer.WriteString("\n## Overlapping address spaces\n\n")
+
+	if len(overlaps) == 0 {
+		buffer.WriteString("No overlapping address spaces between connected virtual networks.\n")
+		return
+	}
+
+	buffer.WriteString("| Virtual network | Address prefix | Virtual network | Address prefix | Connected via |\n")
+	buffer.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, overlap := range overlaps {
+		buffer.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", overlap.A.Resource.Name, overlap.PrefixA, overlap.B.Resource.Name, overlap.PrefixB, overlap.Via))
+	}
+}
+
+func bar(prefix netip.Prefix, used []netip.Prefix) string {
+	// every cell covers an equal part of the prefix. Small prefixes get one cell per address
+	cellBits := min(prefix.Bits()+BAR_BITS, prefix.Addr().BitLen())
+	cells := 1 << (cellBits - prefix.Bits())
+
+	var builder strings.Builder
+
+	for _, cell := range cellPrefixes(prefix, cellBits, cells) {
+		usedInCell := addressSpace.Size(list.FlatMap(used, func(u netip.Prefix) []netip.Prefix {
+			if !u.Overlaps(cell) {
+				return nil
+			}
+
+			// prefixes are aligned, so the overlap is always the smaller of the two
+			if u.Bits() > cell.Bits() {
+				return []netip.Prefix{u}
+			}
+
+			return []netip.Prefix{cell}
+		}))
+
+		if usedInCell.Sign() == 0 {
+			builder.WriteString(FREE)
+		} else if usedInCell.Cmp(addressSpace.Size([]netip.Prefix{cell})) == 0 {
+			builder.WriteString(USED)
+		} else {
+			builder.WriteString(PARTIAL)
+		}
+	}
+
+	return builder.String()
+}
+
+func cellPrefixes(prefix netip.Prefix, cellBits, cells int) []netip.Prefix {
+	result := []netip.Prefix{}
+
+	address := prefix.Addr()
+	step := new(big.Int).Lsh(big.NewInt(1), uint(address.BitLen()-cellBits))
+	current := new(big.Int).SetBytes(address.AsSlice())
+
+	for range cells {
+		bytes := current.FillBytes(make([]byte, address.BitLen()/8))
+		cellAddress, _ := netip.AddrFromSlice(bytes)
+
+		result = append(result, netip.PrefixFrom(cellAddress, cellBits))
+
+		current.Add(current, step)
+	}
+
+	return result
+}
+
+func joinPrefixes(prefixes []netip.Prefix) string {
+	return strings.Join(list.Map(prefixes, func(p netip.Prefix) string { return p.String() }), ", ")
+}
@@ -0,0 +1,116 @@
+package jsongraph
+
+import (
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/containment"
+	"cloudsketch/internal/frontends/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type jsongraph struct {
+}
+
+func New() *jsongraph {
+	return &jsongraph{}
+}
+
+// Graph follows the elements format of Cytoscape.js, which Cytoscape desktop imports as well
+type Graph struct {
+	Data     GraphData `json:"data"`
+	Elements Elements  `json:"elements"`
+}
+
+type GraphData struct {
+	Name string `json:"name"`
+}
+
+type Elements struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+}
+
+type Node struct {
+	Data NodeData `json:"data"`
+}
+
+type NodeData struct {
+	Id            string              `json:"id"`
+	Name          string              `json:"name"`
+	Type          string              `json:"type"`
+	ResourceGroup string              `json:"resourceGroup,omitempty"`
+	Parent        string              `json:"parent,omitempty"`
+	Properties    map[string][]string `json:"properties"`
+}
+
+type Edge struct {
+	Data EdgeData `json:"data"`
+}
+
+type EdgeData struct {
+	Id     string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+}
+
+func (j *jsongraph) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	graph := ToGraph(resources, options.Name)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(graph)
+}
+
+func ToGraph(resources []*models.Resource, name string) *Graph {
+	tree := containment.New(resources)
+
+	graph := &Graph{
+		Data: GraphData{Name: name},
+		Elements: Elements{
+			Nodes: []*Node{},
+			Edges: []*Edge{},
+		},
+	}
+
+	for _, resource := range tree.Resources() {
+		data := NodeData{
+			Id:            resource.Id,
+			Name:          resource.Name,
+			Type:          resource.Type,
+			ResourceGroup: resource.GetResourceGroup(),
+			Properties:    resource.Properties,
+		}
+
+		if data.Properties == nil {
+			data.Properties = map[string][]string{}
+		}
+
+		// compound nodes in Cytoscape
+		if parent := tree.Parent(resource); parent != nil {
+			data.Parent = parent.Id
+		}
+
+		graph.Elements.Nodes = append(graph.Elements.Nodes, &Node{Data: data})
+	}
+
+	for i, edge := range tree.Edges() {
+		graph.Elements.Edges = append(graph.Elements.Edges, &Edge{
+			Data: EdgeData{
+				Id:     fmt.Sprintf("e%v", i),
+				Source: edge.Source.Id,
+				Target: edge.Target.Id,
+				Kind:   edge.Kind,
+			},
+		})
+	}
+
+	return graph
+}
@@ -1,9 +1,17 @@
 package models
 
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
 type Resource struct {
 	Id, Type, Name string
 	DependsOn      []*Resource
-	Properties     map[string][]string
+	// dependencies that were removed from DependsOn because they closed a cycle
+	CyclicDependsOn []*Resource
+	Properties      map[string][]string
 }
 
 func (r *Resource) GetLinkOrDefault() *string {
@@ -14,3 +22,58 @@ func (r *Resource) GetLinkOrDefault() *string {
 
 	return nil
 }
+
+func (r *Resource) GetResourceGroup() string {
+	// /subscriptions/<id>/resourcegroups/<name>/providers/...
+	segments := strings.Split(r.Id, "/")
+
+	for i, segment := range segments {
+		if strings.ToLower(segment) != "resourcegroups" || i+1 >= len(segments) {
+			continue
+		}
+
+		return segments[i+1]
+	}
+
+	return ""
+}
+
+// Clone copies the resources, so they can be modified without affecting the caller. Dependencies on the given resources point to their copies
+func Clone(resources []*Resource) []*Resource {
+	clones := map[string]*Resource{}
+
+	for _, r := range resources {
+		clone := *r
+		clone.Properties = maps.Clone(r.Properties)
+
+		for key, values := range clone.Properties {
+			clone.Properties[key] = slices.Clone(values)
+		}
+
+		clones[r.Id] = &clone
+	}
+
+	toClone := func(dependencies []*Resource) []*Resource {
+		result := make([]*Resource, len(dependencies))
+
+		for i, dependency := range dependencies {
+			result[i] = dependency
+
+			if clone, ok := clones[dependency.Id]; ok {
+				result[i] = clone
+			}
+		}
+
+		return result
+	}
+
+	result := make([]*Resource, len(resources))
+
+	for i, r := range resources {
+		result[i] = clones[r.Id]
+		result[i].DependsOn = toClone(r.DependsOn)
+		result[i].CyclicDependsOn = toClone(r.CyclicDependsOn)
+	}
+
+	return result
+}
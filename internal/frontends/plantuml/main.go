@@ -0,0 +1,135 @@
+package plantuml
+
+import (
+	"bytes"
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/containment"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	DEFAULT_NAME = "cloudsketch"
+)
+
+var (
+	// PlantUML element used for the blocks around the resources in a subscription, virtual network or subnet
+	blocks = map[string]string{
+		types.SUBSCRIPTION:    "rectangle",
+		types.VIRTUAL_NETWORK: "package",
+		types.SUBNET:          "rectangle",
+	}
+)
+
+type plantuml struct {
+}
+
+func New() *plantuml {
+	return &plantuml{}
+}
+
+func (p *plantuml) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	content := ToPlantUML(resources, options.Name)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, content)
+
+	return err
+}
+
+func ToPlantUML(resources []*models.Resource, name string) string {
+	if name == "" {
+		name = DEFAULT_NAME
+	}
+
+	tree := containment.New(resources)
+	aliases := tree.Aliases()
+
+	var buffer bytes.Buffer
+
+	buffer.WriteString(fmt.Sprintf("@startuml %s\n", containment.Alias(name)))
+	buffer.WriteString("!include <azure/AzureCommon>\n")
+
+	for _, include := range includes(tree.Resources()) {
+		buffer.WriteString(fmt.Sprintf("!include <azure/%s>\n", include))
+	}
+
+	buffer.WriteString("\nleft to right direction\n")
+	buffer.WriteString(fmt.Sprintf("title %s\n\n", escape(name)))
+
+	for _, resource := range tree.Roots() {
+		writeResource(&buffer, tree, aliases, resource, 0)
+	}
+
+	buffer.WriteString("\n")
+
+	for _, resource := range tree.Resources() {
+		for _, dependency := range tree.Dependencies(resource) {
+			buffer.WriteString(fmt.Sprintf("%s --> %s\n", aliases[resource.Id], aliases[dependency.Id]))
+		}
+
+		// dependencies that were removed to break a cycle
+		for _, dependency := range tree.CyclicDependencies(resource) {
+			buffer.WriteString(fmt.Sprintf("%s ..> %s\n", aliases[resource.Id], aliases[dependency.Id]))
+		}
+	}
+
+	buffer.WriteString("@enduml\n")
+
+	return buffer.String()
+}
+
+func writeResource(buffer *bytes.Buffer, tree *containment.Tree, aliases map[string]string, resource *models.Resource, depth int) {
+	indent := strings.Repeat("  ", depth)
+	alias := aliases[resource.Id]
+	children := tree.Children(resource)
+
+	if len(children) == 0 {
+		buffer.WriteString(fmt.Sprintf("%s%s\n", indent, element(resource, alias)))
+		return
+	}
+
+	buffer.WriteString(fmt.Sprintf("%s%s \"%s\" <<%s>> as %s_group {\n", indent, blocks[resource.Type], escape(resource.Name), types.Readable(resource.Type), alias))
+	buffer.WriteString(fmt.Sprintf("%s  %s\n", indent, element(resource, alias)))
+
+	for _, child := range children {
+		writeResource(buffer, tree, aliases, child, depth+1)
+	}
+
+	buffer.WriteString(fmt.Sprintf("%s}\n", indent))
+}
+
+func element(resource *models.Resource, alias string) string {
+	sprite, ok := sprites[resource.Type]
+
+	if !ok {
+		return fmt.Sprintf("rectangle \"%s\\n%s\" <<%s>> as %s", escape(resource.Name), types.Readable(resource.Type), types.Readable(resource.Type), alias)
+	}
+
+	return fmt.Sprintf("%s(%s, \"%s\", \"%s\")", sprite.macro, alias, escape(resource.Name), types.Readable(resource.Type))
+}
+
+func includes(resources []*models.Resource) []string {
+	result := map[string]bool{}
+
+	for _, resource := range resources {
+		if sprite, ok := sprites[resource.Type]; ok {
+			result[sprite.include] = true
+		}
+	}
+
+	return slices.Sorted(maps.Keys(result))
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(s, `"`, `'`)
+}
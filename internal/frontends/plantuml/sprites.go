@@ -0,0 +1,50 @@
+package plantuml
+
+import "cloudsketch/internal/frontends/types"
+
+type sprite struct {
+	// path of the Azure-PlantUML file in the PlantUML standard library
+	include string
+	macro   string
+}
+
+// Azure-PlantUML symbols of the types that have one. Other types are drawn as plain rectangles
+var sprites = map[string]sprite{
+	types.AI_SERVICES:                        {"AIMachineLearning/AzureCognitiveServices", "AzureCognitiveServices"},
+	types.API_MANAGEMENT_API:                 {"Integration/AzureAPIManagement", "AzureAPIManagement"},
+	types.API_MANAGEMENT_SERVICE:             {"Integration/AzureAPIManagement", "AzureAPIManagement"},
+	types.APP_SERVICE:                        {"Compute/AzureAppService", "AzureAppService"},
+	types.APPLICATION_GATEWAY:                {"Networking/AzureApplicationGateway", "AzureApplicationGateway"},
+	types.APPLICATION_GROUP:                  {"Compute/AzureWindowsVirtualDesktop", "AzureWindowsVirtualDesktop"},
+	types.APPLICATION_INSIGHTS:               {"DevOps/AzureApplicationInsights", "AzureApplicationInsights"},
+	types.BASTION:                            {"Networking/AzureBastion", "AzureBastion"},
+	types.CONTAINER_REGISTRY:                 {"Containers/AzureContainerRegistry", "AzureContainerRegistry"},
+	types.COSMOS:                             {"Databases/AzureCosmosDb", "AzureCosmosDb"},
+	types.DATA_FACTORY:                       {"Analytics/AzureDataFactory", "AzureDataFactory"},
+	types.DATABRICKS_WORKSPACE:               {"Analytics/AzureDatabricks", "AzureDatabricks"},
+	types.DNS_RECORD:                         {"Networking/AzureDNS", "AzureDNS"},
+	types.EXPRESS_ROUTE_CIRCUIT:              {"Networking/AzureExpressRoute", "AzureExpressRoute"},
+	types.EXPRESS_ROUTE_GATEWAY:              {"Networking/AzureExpressRoute", "AzureExpressRoute"},
+	types.FUNCTION_APP:                       {"Compute/AzureFunction", "AzureFunction"},
+	types.HOST_POOL:                          {"Compute/AzureWindowsVirtualDesktop", "AzureWindowsVirtualDesktop"},
+	types.KEY_VAULT:                          {"Security/AzureKeyVault", "AzureKeyVault"},
+	types.LOAD_BALANCER:                      {"Networking/AzureLoadBalancer", "AzureLoadBalancer"},
+	types.LOG_ANALYTICS:                      {"Analytics/AzureLogAnalytics", "AzureLogAnalytics"},
+	types.LOGIC_APP:                          {"Integration/AzureLogicApps", "AzureLogicApps"},
+	types.MACHINE_LEARNING_WORKSPACE:         {"AIMachineLearning/AzureMachineLearning", "AzureMachineLearning"},
+	types.NETWORK_SECURITY_GROUP:             {"Networking/AzureNetworkSecurityGroup", "AzureNetworkSecurityGroup"},
+	types.POSTGRES_SQL_SERVER:                {"Databases/AzureDatabaseForPostgreSQL", "AzureDatabaseForPostgreSQL"},
+	types.PRIVATE_DNS_ZONE:                   {"Networking/AzureDNS", "AzureDNS"},
+	types.REDIS:                              {"Databases/AzureRedisCache", "AzureRedisCache"},
+	types.SEARCH_SERVICE:                     {"AIMachineLearning/AzureSearch", "AzureSearch"},
+	types.SIGNALR:                            {"Web/AzureSignalR", "AzureSignalR"},
+	types.SQL_DATABASE:                       {"Databases/AzureSqlDatabase", "AzureSqlDatabase"},
+	types.STORAGE_ACCOUNT:                    {"Storage/AzureStorage", "AzureStorage"},
+	types.USER_ASSIGNED_IDENTITY:             {"Identity/AzureManagedIdentity", "AzureManagedIdentity"},
+	types.VIRTUAL_MACHINE:                    {"Compute/AzureVirtualMachine", "AzureVirtualMachine"},
+	types.VIRTUAL_MACHINE_SCALE_SET:          {"Compute/AzureVirtualMachineScaleSet", "AzureVirtualMachineScaleSet"},
+	types.VIRTUAL_MACHINE_SCALE_SET_INSTANCE: {"Compute/AzureVirtualMachine", "AzureVirtualMachine"},
+	types.VIRTUAL_NETWORK:                    {"Networking/AzureVirtualNetwork", "AzureVirtualNetwork"},
+	types.VIRTUAL_NETWORK_GATEWAY:            {"Networking/AzureVPNGateway", "AzureVPNGateway"},
+	types.WORKSPACE:                          {"Compute/AzureWindowsVirtualDesktop", "AzureWindowsVirtualDesktop"},
+}
@@ -0,0 +1,252 @@
+package structurizr
+
+import (
+	"bytes"
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/containment"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/list"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	DEFAULT_NAME = "cloudsketch"
+	ENVIRONMENT  = "Azure"
+	INDENT       = "    "
+
+	// the resources are modelled as a single software system named after the diagram
+	SYSTEM_ALIAS = "system"
+	// groups resources that are not in a subscription, since only deployment nodes can be at the root of an environment
+	UNCONTAINED_ALIAS = "azure"
+
+	TAG_DATABASE = "Database"
+	TAG_CYCLIC   = "Cyclic"
+)
+
+var (
+	// resources that run code are containers of the software system, deployed as container instances
+	CONTAINERS = []string{
+		types.APP_SERVICE,
+		types.CONTAINER_APP,
+		types.FUNCTION_APP,
+		types.LOGIC_APP,
+		types.STATIC_WEB_APP,
+		types.VIRTUAL_MACHINE,
+		types.VIRTUAL_MACHINE_SCALE_SET,
+	}
+	// data stores are containers as well, drawn as cylinders
+	DATABASES = []string{
+		types.COSMOS,
+		types.POSTGRES_SQL_SERVER,
+		types.REDIS,
+		types.SQL_DATABASE,
+		types.SQL_SERVER,
+		types.STORAGE_ACCOUNT,
+	}
+)
+
+type structurizr struct {
+}
+
+func New() *structurizr {
+	return &structurizr{}
+}
+
+func (s *structurizr) WriteDiagram(ctx context.Context, w io.Writer, resources []*models.Resource, options *frontends.Options) error {
+	content := ToDSL(resources, options.Name)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, content)
+
+	return err
+}
+
+type workspace struct {
+	buffer  bytes.Buffer
+	tree    *containment.Tree
+	aliases map[string]string
+}
+
+func ToDSL(resources []*models.Resource, name string) string {
+	if name == "" {
+		name = DEFAULT_NAME
+	}
+
+	tree := containment.New(resources)
+
+	w := &workspace{
+		tree:    tree,
+		aliases: tree.Aliases(),
+	}
+
+	w.line(0, "workspace %s {", quote(name))
+	w.line(1, "model {")
+
+	w.writeSoftwareSystem(name)
+	w.writeDeploymentEnvironment()
+	w.writeRelationships()
+
+	w.line(1, "}")
+	w.line(0, "")
+	w.writeViews()
+	w.line(0, "}")
+
+	return w.buffer.String()
+}
+
+func (w *workspace) writeSoftwareSystem(name string) {
+	containers := list.Filter(w.tree.Resources(), isContainer)
+
+	w.line(2, "%s = softwareSystem %s {", SYSTEM_ALIAS, quote(name))
+
+	for _, resource := range containers {
+		tags := ""
+
+		if slices.Contains(DATABASES, resource.Type) {
+			tags = " " + quote(TAG_DATABASE)
+		}
+
+		w.line(3, "%s = container %s \"\" %s%s", w.aliases[resource.Id], quote(resource.Name), quote(types.Readable(resource.Type)), tags)
+	}
+
+	w.line(2, "}")
+	w.line(0, "")
+}
+
+func (w *workspace) writeDeploymentEnvironment() {
+	w.line(2, "deploymentEnvironment %s {", quote(ENVIRONMENT))
+
+	roots := w.tree.Roots()
+	deploymentNodes, uncontained := list.Split(roots, isDeploymentNode)
+
+	for _, resource := range deploymentNodes {
+		w.writeResource(resource, 3)
+	}
+
+	if len(uncontained) > 0 {
+		w.line(3, "%s = deploymentNode %s {", UNCONTAINED_ALIAS, quote(ENVIRONMENT))
+
+		for _, resource := range uncontained {
+			w.writeResource(resource, 4)
+		}
+
+		w.line(3, "}")
+	}
+
+	w.line(2, "}")
+	w.line(0, "")
+}
+
+func (w *workspace) writeResource(resource *models.Resource, depth int) {
+	alias := w.aliases[resource.Id]
+	technology := quote(types.Readable(resource.Type))
+
+	if isDeploymentNode(resource) {
+		w.line(depth, "%s = deploymentNode %s \"\" %s {", alias, quote(resource.Name), technology)
+
+		for _, child := range w.tree.Children(resource) {
+			w.writeResource(child, depth+1)
+		}
+
+		w.line(depth, "}")
+
+		return
+	}
+
+	if isContainer(resource) {
+		w.line(depth, "%s = containerInstance %s", instance(alias), alias)
+		return
+	}
+
+	w.line(depth, "%s = infrastructureNode %s \"\" %s", alias, quote(resource.Name), technology)
+}
+
+func (w *workspace) writeRelationships() {
+	for _, resource := range w.tree.Resources() {
+		for _, dependency := range w.tree.Dependencies(resource) {
+			w.line(2, "%s -> %s \"Depends on\"", w.deploymentAlias(resource), w.deploymentAlias(dependency))
+		}
+
+		// dependencies that were removed to break a cycle
+		for _, dependency := range w.tree.CyclicDependencies(resource) {
+			w.line(2, "%s -> %s \"Depends on\" \"\" %s", w.deploymentAlias(resource), w.deploymentAlias(dependency), quote(TAG_CYCLIC))
+		}
+	}
+}
+
+func (w *workspace) writeViews() {
+	w.line(1, "views {")
+
+	roots := list.Filter(w.tree.Roots(), isDeploymentNode)
+	views := list.Map(roots, func(r *models.Resource) [2]string {
+		return [2]string{w.aliases[r.Id], r.Name}
+	})
+
+	if len(roots) < len(w.tree.Roots()) {
+		views = append(views, [2]string{UNCONTAINED_ALIAS, ENVIRONMENT})
+	}
+
+	// one deployment view per subscription
+	for _, view := range views {
+		w.line(2, "deployment * %s %s {", quote(ENVIRONMENT), quote(view[0]))
+		w.line(3, "title %s", quote(view[1]))
+		w.line(3, "include %s", view[0])
+		w.line(3, "autoLayout lr")
+		w.line(2, "}")
+		w.line(0, "")
+	}
+
+	w.line(2, "styles {")
+	w.line(3, "element %s {", quote(TAG_DATABASE))
+	w.line(4, "shape Cylinder")
+	w.line(3, "}")
+	w.line(3, "relationship %s {", quote(TAG_CYCLIC))
+	w.line(4, "style dashed")
+	w.line(3, "}")
+	w.line(2, "}")
+	w.line(1, "}")
+}
+
+// deploymentAlias returns the identifier of the element a resource is deployed as
+func (w *workspace) deploymentAlias(resource *models.Resource) string {
+	if isContainer(resource) {
+		return instance(w.aliases[resource.Id])
+	}
+
+	return w.aliases[resource.Id]
+}
+
+func (w *workspace) line(depth int, format string, a ...any) {
+	if format == "" {
+		w.buffer.WriteString("\n")
+		return
+	}
+
+	w.buffer.WriteString(strings.Repeat(INDENT, depth))
+	w.buffer.WriteString(fmt.Sprintf(format, a...))
+	w.buffer.WriteString("\n")
+}
+
+func isDeploymentNode(resource *models.Resource) bool {
+	return slices.Contains(containment.CONTAINERS, resource.Type)
+}
+
+func isContainer(resource *models.Resource) bool {
+	return slices.Contains(CONTAINERS, resource.Type) || slices.Contains(DATABASES, resource.Type)
+}
+
+func instance(alias string) string {
+	return alias + "_instance"
+}
+
+func quote(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `\"`))
+}
@@ -0,0 +1,89 @@
+package types
+
+const (
+	CATEGORY_NETWORK     = "network"
+	CATEGORY_COMPUTE     = "compute"
+	CATEGORY_DATA        = "data"
+	CATEGORY_SECURITY    = "security"
+	CATEGORY_MONITORING  = "monitoring"
+	CATEGORY_INTEGRATION = "integration"
+	// subscriptions and other resources that scope the resources in them
+	CATEGORY_SCOPE = "scope"
+)
+
+var (
+	// the category of every resource type, used to style and group resources
+	CATEGORIES = map[string]string{
+		AI_SERVICES:                           CATEGORY_INTEGRATION,
+		API_MANAGEMENT_API:                    CATEGORY_INTEGRATION,
+		API_MANAGEMENT_SERVICE:                CATEGORY_INTEGRATION,
+		APP_CONFIGURATION:                     CATEGORY_INTEGRATION,
+		APP_SERVICE:                           CATEGORY_COMPUTE,
+		APP_SERVICE_PLAN:                      CATEGORY_COMPUTE,
+		APPLICATION_GATEWAY:                   CATEGORY_NETWORK,
+		APPLICATION_GROUP:                     CATEGORY_COMPUTE,
+		APPLICATION_INSIGHTS:                  CATEGORY_MONITORING,
+		APPLICATION_SECURITY_GROUP:            CATEGORY_NETWORK,
+		BACKEND_ADDRESS_POOL:                  CATEGORY_NETWORK,
+		BASTION:                               CATEGORY_NETWORK,
+		CONNECTION:                            CATEGORY_NETWORK,
+		CONTAINER_APP:                         CATEGORY_COMPUTE,
+		CONTAINER_APPS_ENVIRONMENT:            CATEGORY_COMPUTE,
+		CONTAINER_REGISTRY:                    CATEGORY_COMPUTE,
+		COSMOS:                                CATEGORY_DATA,
+		DATA_FACTORY:                          CATEGORY_DATA,
+		DATA_FACTORY_INTEGRATION_RUNTIME:      CATEGORY_DATA,
+		DATA_FACTORY_MANAGED_PRIVATE_ENDPOINT: CATEGORY_DATA,
+		DATABRICKS_WORKSPACE:                  CATEGORY_DATA,
+		DNS_RECORD:                            CATEGORY_NETWORK,
+		EXPRESS_ROUTE_CIRCUIT:                 CATEGORY_NETWORK,
+		EXPRESS_ROUTE_GATEWAY:                 CATEGORY_NETWORK,
+		FUNCTION_APP:                          CATEGORY_COMPUTE,
+		HOST_POOL:                             CATEGORY_COMPUTE,
+		KEY_VAULT:                             CATEGORY_SECURITY,
+		LOAD_BALANCER:                         CATEGORY_NETWORK,
+		LOAD_BALANCER_FRONTEND:                CATEGORY_NETWORK,
+		LOG_ANALYTICS:                         CATEGORY_MONITORING,
+		LOGIC_APP:                             CATEGORY_INTEGRATION,
+		MACHINE_LEARNING_WORKSPACE:            CATEGORY_INTEGRATION,
+		NAT_GATEWAY:                           CATEGORY_NETWORK,
+		NETWORK_INTERFACE:                     CATEGORY_NETWORK,
+		NETWORK_SECURITY_GROUP:                CATEGORY_NETWORK,
+		POSTGRES_SQL_SERVER:                   CATEGORY_DATA,
+		PRIVATE_DNS_RESOLVER:                  CATEGORY_NETWORK,
+		PRIVATE_DNS_ZONE:                      CATEGORY_NETWORK,
+		PRIVATE_ENDPOINT:                      CATEGORY_NETWORK,
+		PRIVATE_LINK_SERVICE:                  CATEGORY_NETWORK,
+		PUBLIC_IP_ADDRESS:                     CATEGORY_NETWORK,
+		RECOVERY_SERVICE_VAULT:                CATEGORY_DATA,
+		REDIS:                                 CATEGORY_DATA,
+		SIGNALR:                               CATEGORY_INTEGRATION,
+		ROUTE_TABLE:                           CATEGORY_NETWORK,
+		SEARCH_SERVICE:                        CATEGORY_DATA,
+		SQL_DATABASE:                          CATEGORY_DATA,
+		SQL_SERVER:                            CATEGORY_DATA,
+		STATIC_WEB_APP:                        CATEGORY_COMPUTE,
+		STORAGE_ACCOUNT:                       CATEGORY_DATA,
+		SUBNET:                                CATEGORY_NETWORK,
+		SUBSCRIPTION:                          CATEGORY_SCOPE,
+		USER_ASSIGNED_IDENTITY:                CATEGORY_SECURITY,
+		VIRTUAL_HUB:                           CATEGORY_NETWORK,
+		VIRTUAL_MACHINE:                       CATEGORY_COMPUTE,
+		VIRTUAL_MACHINE_SCALE_SET:             CATEGORY_COMPUTE,
+		VIRTUAL_MACHINE_SCALE_SET_INSTANCE:    CATEGORY_COMPUTE,
+		VIRTUAL_NETWORK:                       CATEGORY_NETWORK,
+		VIRTUAL_NETWORK_GATEWAY:               CATEGORY_NETWORK,
+		VIRTUAL_WAN:                           CATEGORY_NETWORK,
+		WORKSPACE:                             CATEGORY_COMPUTE,
+	}
+)
+
+// Category returns the category of a resource type, or an empty string for unknown types
+func Category(typ string) string {
+	return CATEGORIES[typ]
+}
+
+// Categories returns every category, sorted
+func Categories() []string {
+	return []string{CATEGORY_COMPUTE, CATEGORY_DATA, CATEGORY_INTEGRATION, CATEGORY_MONITORING, CATEGORY_NETWORK, CATEGORY_SCOPE, CATEGORY_SECURITY}
+}
@@ -1,5 +1,7 @@
 package types
 
+import "strings"
+
 const (
 	AI_SERVICES                           = "AI_SERVICES"
 	API_MANAGEMENT_API                    = "API_MANAGEMENT_API"
@@ -63,3 +65,14 @@ const (
 	VIRTUAL_WAN                           = "VIRTUAL_WAN"
 	WORKSPACE                             = "WORKSPACE"
 )
+
+// Readable turns a type like VIRTUAL_NETWORK into Virtual network
+func Readable(typ string) string {
+	s := strings.ReplaceAll(strings.ToLower(typ), "_", " ")
+
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
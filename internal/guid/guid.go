@@ -1,13 +1,28 @@
 package guid
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
 )
 
-func NewGuidAlphanumeric() string {
-	id := uuid.New()
+// Sequence generates alphanumeric ids derived from a seed. The same seed always yields the same ids in the same order
+type Sequence struct {
+	namespace uuid.UUID
+	counter   int
+}
+
+func NewSequence(seed string) *Sequence {
+	return &Sequence{
+		namespace: uuid.NewSHA1(uuid.NameSpaceOID, []byte(seed)),
+	}
+}
+
+func (s *Sequence) Next() string {
+	s.counter++
+
+	id := uuid.NewSHA1(s.namespace, []byte(strconv.Itoa(s.counter)))
 
 	return strings.ReplaceAll(id.String(), "-", "")
 }
@@ -0,0 +1,298 @@
+package ipam
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/list"
+	"fmt"
+	"maps"
+	"math/big"
+	"net/netip"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	ADDRESS_PREFIXES        = "addressPrefixes"
+	REMOTE_VIRTUAL_NETWORKS = "remoteVirtualNetworks"
+)
+
+type VirtualNetwork struct {
+	Resource *models.Resource
+	Prefixes []netip.Prefix
+	Subnets  []*Subnet
+}
+
+type Subnet struct {
+	Resource *models.Resource
+	Prefixes []netip.Prefix
+}
+
+type Overlap struct {
+	A, B             *VirtualNetwork
+	PrefixA, PrefixB netip.Prefix
+	// how the virtual networks are connected. Either peering or the name of the shared hub
+	Via string
+}
+
+func Plan(resources []*models.Resource) []*VirtualNetwork {
+	vnets := map[string]*VirtualNetwork{}
+
+	for _, resource := range resources {
+		if resource.Type != types.VIRTUAL_NETWORK {
+			continue
+		}
+
+		vnets[resource.Id] = &VirtualNetwork{
+			Resource: resource,
+			Prefixes: parsePrefixes(resource),
+			Subnets:  []*Subnet{},
+		}
+	}
+
+	for _, resource := range resources {
+		if resource.Type != types.SUBNET {
+			continue
+		}
+
+		for _, dependency := range resource.DependsOn {
+			vnet, ok := vnets[dependency.Id]
+
+			if !ok {
+				continue
+			}
+
+			vnet.Subnets = append(vnet.Subnets, &Subnet{
+				Resource: resource,
+				Prefixes: parsePrefixes(resource),
+			})
+		}
+	}
+
+	plan := []*VirtualNetwork{}
+
+	for _, vnet := range vnets {
+		sort.Slice(vnet.Subnets, func(i, j int) bool {
+			return comparePrefixes(vnet.Subnets[i].Prefixes, vnet.Subnets[j].Prefixes, vnet.Subnets[i].Resource.Name, vnet.Subnets[j].Resource.Name)
+		})
+
+		plan = append(plan, vnet)
+	}
+
+	sort.Slice(plan, func(i, j int) bool {
+		return comparePrefixes(plan[i].Prefixes, plan[j].Prefixes, plan[i].Resource.Name, plan[j].Resource.Name)
+	})
+
+	return plan
+}
+
+func parsePrefixes(resource *models.Resource) []netip.Prefix {
+	prefixes := []netip.Prefix{}
+
+	for _, value := range resource.Properties[ADDRESS_PREFIXES] {
+		prefix, err := netip.ParsePrefix(value)
+
+		// snapshots taken before address prefixes were captured or hand edited values are skipped
+		if err != nil {
+			continue
+		}
+
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes
+}
+
+func comparePrefixes(a, b []netip.Prefix, nameA, nameB string) bool {
+	// resources without a known address space are listed last
+	if len(a) == 0 || len(b) == 0 {
+		if len(a) == len(b) {
+			return nameA < nameB
+		}
+
+		return len(a) > 0
+	}
+
+	if c := a[0].Addr().Compare(b[0].Addr()); c != 0 {
+		return c < 0
+	}
+
+	return nameA < nameB
+}
+
+func Size(prefixes []netip.Prefix) *big.Int {
+	total := big.NewInt(0)
+
+	for _, prefix := range prefixes {
+		total.Add(total, addresses(prefix))
+	}
+
+	return total
+}
+
+func addresses(prefix netip.Prefix) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(prefix.Addr().BitLen()-prefix.Bits()))
+}
+
+func (v *VirtualNetwork) Used() []netip.Prefix {
+	return list.FlatMap(v.Subnets, func(s *Subnet) []netip.Prefix { return s.Prefixes })
+}
+
+// Free returns the largest prefixes of the virtual network address space that are not used by any subnet
+func (v *VirtualNetwork) Free() []netip.Prefix {
+	used := v.Used()
+
+	return list.FlatMap(v.Prefixes, func(p netip.Prefix) []netip.Prefix {
+		return free(p, used)
+	})
+}
+
+func free(prefix netip.Prefix, used []netip.Prefix) []netip.Prefix {
+	overlapping := list.Filter(used, func(u netip.Prefix) bool { return u.Overlaps(prefix) })
+
+	if len(overlapping) == 0 {
+		return []netip.Prefix{prefix}
+	}
+
+	// a used prefix of equal or larger size covers the whole prefix
+	if list.Contains(overlapping, func(u netip.Prefix) bool { return u.Bits() <= prefix.Bits() }) {
+		return []netip.Prefix{}
+	}
+
+	lower, upper := split(prefix)
+
+	return append(free(lower, overlapping), free(upper, overlapping)...)
+}
+
+func split(prefix netip.Prefix) (netip.Prefix, netip.Prefix) {
+	bits := prefix.Bits() + 1
+
+	address := prefix.Addr().AsSlice()
+	lower, _ := netip.AddrFromSlice(address)
+
+	bit := prefix.Bits()
+	address[bit/8] |= 1 << (7 - bit%8)
+	upper, _ := netip.AddrFromSlice(address)
+
+	return netip.PrefixFrom(lower, bits), netip.PrefixFrom(upper, bits)
+}
+
+// Overlaps returns address ranges that overlap between virtual networks that can reach each other
+func Overlaps(resources []*models.Resource) []*Overlap {
+	plan := Plan(resources)
+
+	vnets := map[string]*VirtualNetwork{}
+
+	for _, vnet := range plan {
+		vnets[vnet.Resource.Id] = vnet
+	}
+
+	connections := connections(resources, vnets)
+
+	overlaps := []*Overlap{}
+
+	for i, a := range plan {
+		for _, b := range plan[i+1:] {
+			via, ok := connections[key(a, b)]
+
+			if !ok {
+				continue
+			}
+
+			for _, prefixA := range a.Prefixes {
+				for _, prefixB := range b.Prefixes {
+					if !prefixA.Overlaps(prefixB) {
+						continue
+					}
+
+					overlaps = append(overlaps, &Overlap{
+						A:       a,
+						B:       b,
+						PrefixA: prefixA,
+						PrefixB: prefixB,
+						Via:     via,
+					})
+				}
+			}
+		}
+	}
+
+	return overlaps
+}
+
+func connections(resources []*models.Resource, vnets map[string]*VirtualNetwork) map[string]string {
+	connections := map[string]string{}
+
+	// peerings are listed on either side, the partners of a virtual network include both directions
+	partners := map[string][]*VirtualNetwork{}
+
+	for _, vnet := range sortedById(vnets) {
+		for _, remoteId := range vnet.Resource.Properties[REMOTE_VIRTUAL_NETWORKS] {
+			remote, ok := vnets[strings.ToLower(remoteId)]
+
+			if !ok || remote == vnet {
+				continue
+			}
+
+			if _, ok := connections[key(vnet, remote)]; !ok {
+				partners[vnet.Resource.Id] = append(partners[vnet.Resource.Id], remote)
+				partners[remote.Resource.Id] = append(partners[remote.Resource.Id], vnet)
+			}
+
+			connections[key(vnet, remote)] = "peering"
+		}
+	}
+
+	// spokes peered with the same hub virtual network reach each other through it
+	for _, hub := range sortedById(vnets) {
+		connectAll(connections, partners[hub.Resource.Id], fmt.Sprintf("hub %s", hub.Resource.Name))
+	}
+
+	// every virtual network connected to a hub can reach every other virtual network connected to the same hub
+	for _, hub := range resources {
+		if hub.Type != types.VIRTUAL_HUB {
+			continue
+		}
+
+		connected := list.Filter(list.Map(hub.Properties[REMOTE_VIRTUAL_NETWORKS], func(id string) *VirtualNetwork {
+			return vnets[strings.ToLower(id)]
+		}), func(v *VirtualNetwork) bool { return v != nil })
+
+		connectAll(connections, connected, fmt.Sprintf("hub %s", hub.Name))
+	}
+
+	return connections
+}
+
+// connectAll connects every pair of virtual networks that is not connected yet
+func connectAll(connections map[string]string, connected []*VirtualNetwork, via string) {
+	for i, a := range connected {
+		for _, b := range connected[i+1:] {
+			if _, ok := connections[key(a, b)]; ok {
+				continue
+			}
+
+			connections[key(a, b)] = via
+		}
+	}
+}
+
+// sortedById keeps the connection found first, and so the reported hub, stable between runs
+func sortedById(vnets map[string]*VirtualNetwork) []*VirtualNetwork {
+	sorted := slices.Collect(maps.Values(vnets))
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Resource.Id < sorted[j].Resource.Id
+	})
+
+	return sorted
+}
+
+func key(a, b *VirtualNetwork) string {
+	if a.Resource.Id > b.Resource.Id {
+		a, b = b, a
+	}
+
+	return fmt.Sprintf("%s|%s", a.Resource.Id, b.Resource.Id)
+}
@@ -0,0 +1,124 @@
+package ipam
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func vnet(id string, prefixes []string, peerings ...string) *models.Resource {
+	return &models.Resource{Id: id, Type: types.VIRTUAL_NETWORK, Name: id, Properties: map[string][]string{
+		ADDRESS_PREFIXES:        prefixes,
+		REMOTE_VIRTUAL_NETWORKS: peerings,
+	}}
+}
+
+func subnet(id, prefix string, vnet *models.Resource) *models.Resource {
+	return &models.Resource{Id: id, Type: types.SUBNET, Name: id, DependsOn: []*models.Resource{vnet}, Properties: map[string][]string{
+		ADDRESS_PREFIXES: {prefix},
+	}}
+}
+
+func prefixes(values ...string) []netip.Prefix {
+	result := []netip.Prefix{}
+
+	for _, value := range values {
+		result = append(result, netip.MustParsePrefix(value))
+	}
+
+	return result
+}
+
+func TestFree(t *testing.T) {
+	network := vnet("vnet", []string{"10.0.0.0/16", "192.168.0.0/24"})
+
+	for name, tc := range map[string]struct {
+		subnets  []*models.Resource
+		expected []netip.Prefix
+	}{
+		"empty":      {[]*models.Resource{}, prefixes("10.0.0.0/16", "192.168.0.0/24")},
+		"first half": {[]*models.Resource{subnet("a", "10.0.0.0/17", network)}, prefixes("10.0.128.0/17", "192.168.0.0/24")},
+		"gaps": {[]*models.Resource{
+			subnet("a", "10.0.0.0/24", network),
+			subnet("b", "10.0.2.0/24", network),
+			subnet("c", "192.168.0.0/24", network),
+		}, prefixes("10.0.1.0/24", "10.0.3.0/24", "10.0.4.0/22", "10.0.8.0/21", "10.0.16.0/20", "10.0.32.0/19", "10.0.64.0/18", "10.0.128.0/17")},
+		"full": {[]*models.Resource{
+			subnet("a", "10.0.0.0/16", network),
+			subnet("b", "192.168.0.0/25", network),
+			subnet("c", "192.168.0.128/25", network),
+		}, prefixes()},
+	} {
+		plan := Plan(append([]*models.Resource{network}, tc.subnets...))
+
+		if actual := plan[0].Free(); !slices.Equal(actual, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, actual)
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	if size := Size(prefixes("10.0.0.0/16", "192.168.0.0/24")); size.Int64() != 65536+256 {
+		t.Errorf("expected %v addresses, got %v", 65536+256, size)
+	}
+}
+
+func TestOverlapsOfPeerings(t *testing.T) {
+	a := vnet("a", []string{"10.0.0.0/16"}, "b")
+	// the peering is only listed on one side
+	b := vnet("b", []string{"10.0.128.0/17"})
+
+	overlaps := Overlaps([]*models.Resource{a, b})
+
+	if len(overlaps) != 1 || overlaps[0].Via != "peering" {
+		t.Fatalf("expected one overlap via peering, got %v", len(overlaps))
+	}
+}
+
+func TestOverlapsOfSpokes(t *testing.T) {
+	hub := vnet("hub", []string{"10.0.0.0/24"}, "spoke-a", "spoke-b")
+	spokeA := vnet("spoke-a", []string{"10.1.0.0/16"}, "hub")
+	spokeB := vnet("spoke-b", []string{"10.1.0.0/24"}, "hub")
+	unrelated := vnet("unrelated", []string{"10.1.0.0/16"})
+
+	overlaps := Overlaps([]*models.Resource{hub, spokeA, spokeB, unrelated})
+
+	if len(overlaps) != 1 {
+		t.Fatalf("expected one overlap, got %v", len(overlaps))
+	}
+
+	overlap := overlaps[0]
+
+	if overlap.A.Resource != spokeA && overlap.B.Resource != spokeA {
+		t.Errorf("expected spoke-a to overlap, got %s and %s", overlap.A.Resource.Name, overlap.B.Resource.Name)
+	}
+
+	if overlap.Via != "hub hub" {
+		t.Errorf("expected the spokes to be connected via the hub, got %s", overlap.Via)
+	}
+}
+
+func TestOverlapsOfVirtualHubConnections(t *testing.T) {
+	spokeA := vnet("spoke-a", []string{"10.1.0.0/16"})
+	spokeB := vnet("spoke-b", []string{"10.1.0.0/24"})
+	hub := &models.Resource{Id: "wan-hub", Type: types.VIRTUAL_HUB, Name: "wan-hub", Properties: map[string][]string{
+		REMOTE_VIRTUAL_NETWORKS: {"spoke-a", "spoke-b"},
+	}}
+
+	overlaps := Overlaps([]*models.Resource{spokeA, spokeB, hub})
+
+	if len(overlaps) != 1 || overlaps[0].Via != "hub wan-hub" {
+		t.Fatalf("expected one overlap via the virtual hub, got %v", len(overlaps))
+	}
+}
+
+func TestUnconnectedVirtualNetworksMayOverlap(t *testing.T) {
+	a := vnet("a", []string{"10.0.0.0/16"})
+	b := vnet("b", []string{"10.0.0.0/16"})
+
+	if overlaps := Overlaps([]*models.Resource{a, b}); len(overlaps) != 0 {
+		t.Errorf("expected no overlaps, got %v", len(overlaps))
+	}
+}
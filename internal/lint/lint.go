@@ -0,0 +1,136 @@
+package lint
+
+import (
+	"cloudsketch/internal/frontends/models"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/lint/rules/app_service_without_vnet_integration"
+	"cloudsketch/internal/lint/rules/orphaned_network_interface"
+	"cloudsketch/internal/lint/rules/orphaned_public_ip"
+	"cloudsketch/internal/lint/rules/overlapping_address_space"
+	"cloudsketch/internal/lint/rules/paas_without_private_access"
+	"cloudsketch/internal/lint/rules/subnet_without_nsg"
+	"cloudsketch/internal/lint/rules/unlinked_private_dns_zone"
+	"cloudsketch/internal/list"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+type rule interface {
+	Check([]*models.Resource) []*lintModels.Finding
+}
+
+type Rule struct {
+	Id, Description, Severity string
+	rule                      rule
+}
+
+type Options struct {
+	Disabled     []string
+	Suppressions []Suppression
+}
+
+type Suppression struct {
+	// an empty rule suppresses findings of every rule
+	Rule string
+	// regular expression matched against the resource id
+	Resource string
+	Reason   string
+}
+
+var (
+	rules []*Rule = []*Rule{
+		{app_service_without_vnet_integration.ID, app_service_without_vnet_integration.DESCRIPTION, app_service_without_vnet_integration.SEVERITY, app_service_without_vnet_integration.New()},
+		{orphaned_network_interface.ID, orphaned_network_interface.DESCRIPTION, orphaned_network_interface.SEVERITY, orphaned_network_interface.New()},
+		{orphaned_public_ip.ID, orphaned_public_ip.DESCRIPTION, orphaned_public_ip.SEVERITY, orphaned_public_ip.New()},
+		{overlapping_address_space.ID, overlapping_address_space.DESCRIPTION, overlapping_address_space.SEVERITY, overlapping_address_space.New()},
+		{paas_without_private_access.ID, paas_without_private_access.DESCRIPTION, paas_without_private_access.SEVERITY, paas_without_private_access.New()},
+		{subnet_without_nsg.ID, subnet_without_nsg.DESCRIPTION, subnet_without_nsg.SEVERITY, subnet_without_nsg.New()},
+		{unlinked_private_dns_zone.ID, unlinked_private_dns_zone.DESCRIPTION, unlinked_private_dns_zone.SEVERITY, unlinked_private_dns_zone.New()},
+	}
+
+	severities = map[string]int{
+		lintModels.SEVERITY_NOTE:    1,
+		lintModels.SEVERITY_WARNING: 2,
+		lintModels.SEVERITY_ERROR:   3,
+	}
+)
+
+func Rules() []*Rule {
+	return rules
+}
+
+func Run(resources []*models.Resource, options *Options) ([]*lintModels.Finding, error) {
+	enabledRules := list.Filter(rules, func(r *Rule) bool {
+		return !list.Contains(options.Disabled, func(id string) bool { return id == r.Id })
+	})
+
+	findings := list.FlatMap(enabledRules, func(r *Rule) []*lintModels.Finding {
+		return list.Map(r.rule.Check(resources), func(f *lintModels.Finding) *lintModels.Finding {
+			f.RuleId = r.Id
+
+			// rules lower the severity of findings they cannot be sure about
+			if f.Severity == "" {
+				f.Severity = r.Severity
+			}
+
+			return f
+		})
+	})
+
+	findings, err := removeSuppressed(findings, options.Suppressions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].RuleId != findings[j].RuleId {
+			return findings[i].RuleId < findings[j].RuleId
+		}
+
+		return findings[i].Resource.Id < findings[j].Resource.Id
+	})
+
+	return findings, nil
+}
+
+func removeSuppressed(findings []*lintModels.Finding, suppressions []Suppression) ([]*lintModels.Finding, error) {
+	expressions := make([]*regexp.Regexp, len(suppressions))
+
+	for i, suppression := range suppressions {
+		re, err := regexp.Compile(suppression.Resource)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid suppression expression %s: %+v", suppression.Resource, err)
+		}
+
+		expressions[i] = re
+	}
+
+	return list.Filter(findings, func(f *lintModels.Finding) bool {
+		for i, suppression := range suppressions {
+			if suppression.Rule != "" && suppression.Rule != f.RuleId {
+				continue
+			}
+
+			if expressions[i].MatchString(f.Resource.Id) {
+				return false
+			}
+		}
+
+		return true
+	}), nil
+}
+
+// IsSevere reports whether a finding is at least as severe as the threshold
+func IsSevere(finding *lintModels.Finding, threshold string) bool {
+	minimum, ok := severities[threshold]
+
+	if !ok {
+		// unknown thresholds such as "none" never fail
+		return false
+	}
+
+	return severities[finding.Severity] >= minimum
+}
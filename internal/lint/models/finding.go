@@ -0,0 +1,14 @@
+package models
+
+import "cloudsketch/internal/frontends/models"
+
+const (
+	SEVERITY_ERROR   = "error"
+	SEVERITY_WARNING = "warning"
+	SEVERITY_NOTE    = "note"
+)
+
+type Finding struct {
+	RuleId, Severity, Message string
+	Resource                  *models.Resource
+}
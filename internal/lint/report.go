@@ -0,0 +1,129 @@
+package lint
+
+import (
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/list"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+const (
+	FORMAT_TEXT  = "text"
+	FORMAT_JSON  = "json"
+	FORMAT_SARIF = "sarif"
+
+	SARIF_SCHEMA  = "https://json.schemastore.org/sarif-2.1.0.json"
+	SARIF_VERSION = "2.1.0"
+)
+
+type jsonFinding struct {
+	Rule         string `json:"rule"`
+	Severity     string `json:"severity"`
+	Message      string `json:"message"`
+	ResourceId   string `json:"resourceId"`
+	ResourceName string `json:"resourceName"`
+	ResourceType string `json:"resourceType"`
+}
+
+func Write(w io.Writer, format string, findings []*lintModels.Finding, version string) error {
+	switch format {
+	case FORMAT_TEXT:
+		return writeText(w, findings)
+	case FORMAT_JSON:
+		return writeJSON(w, findings)
+	case FORMAT_SARIF:
+		return writeSARIF(w, findings, version)
+	}
+
+	return fmt.Errorf("unknown format %s", format)
+}
+
+func writeText(w io.Writer, findings []*lintModels.Finding) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, f := range findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", f.Severity, f.RuleId, f.Resource.Name, f.Message)
+	}
+
+	fmt.Fprintf(tw, "%v findings\n", len(findings))
+
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, findings []*lintModels.Finding) error {
+	result := []*jsonFinding{}
+
+	for _, f := range findings {
+		result = append(result, &jsonFinding{
+			Rule:         f.RuleId,
+			Severity:     f.Severity,
+			Message:      f.Message,
+			ResourceId:   f.Resource.Id,
+			ResourceName: f.Resource.Name,
+			ResourceType: f.Resource.Type,
+		})
+	}
+
+	return encode(w, result)
+}
+
+func writeSARIF(w io.Writer, findings []*lintModels.Finding, version string) error {
+	driverRules := list.Map(rules, func(r *Rule) map[string]any {
+		return map[string]any{
+			"id":                   r.Id,
+			"shortDescription":     map[string]any{"text": r.Description},
+			"defaultConfiguration": map[string]any{"level": r.Severity},
+		}
+	})
+
+	results := []map[string]any{}
+
+	for _, f := range findings {
+		results = append(results, map[string]any{
+			"ruleId":  f.RuleId,
+			"level":   f.Severity,
+			"message": map[string]any{"text": fmt.Sprintf("%s: %s", f.Resource.Name, f.Message)},
+			"locations": []map[string]any{
+				{
+					"logicalLocations": []map[string]any{
+						{
+							"name":               f.Resource.Name,
+							"fullyQualifiedName": f.Resource.Id,
+							"kind":               "resource",
+						},
+					},
+				},
+			},
+		})
+	}
+
+	driver := map[string]any{
+		"name":           "cloudsketch",
+		"informationUri": "https://github.com/fremartini/cloudsketch",
+		"rules":          driverRules,
+	}
+
+	if version != "" {
+		driver["version"] = version
+	}
+
+	return encode(w, map[string]any{
+		"$schema": SARIF_SCHEMA,
+		"version": SARIF_VERSION,
+		"runs": []map[string]any{
+			{
+				"tool":    map[string]any{"driver": driver},
+				"results": results,
+			},
+		},
+	})
+}
+
+func encode(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+
+	return encoder.Encode(v)
+}
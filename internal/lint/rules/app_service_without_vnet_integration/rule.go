@@ -0,0 +1,43 @@
+package app_service_without_vnet_integration
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/list"
+)
+
+type rule struct{}
+
+const (
+	ID          = "app-service-without-vnet-integration"
+	DESCRIPTION = "App Services, Function Apps and Logic Apps should route outbound traffic through a virtual network"
+	SEVERITY    = lintModels.SEVERITY_WARNING
+)
+
+var (
+	WEB_APP_TYPES = []string{types.APP_SERVICE, types.FUNCTION_APP, types.LOGIC_APP}
+)
+
+func New() *rule {
+	return &rule{}
+}
+
+func (*rule) Check(resources []*models.Resource) []*lintModels.Finding {
+	withoutIntegration := list.Filter(resources, func(r *models.Resource) bool {
+		if !list.Contains(WEB_APP_TYPES, func(t string) bool { return r.Type == t }) {
+			return false
+		}
+
+		_, ok := r.Properties["outboundSubnet"]
+
+		return !ok
+	})
+
+	return list.Map(withoutIntegration, func(r *models.Resource) *lintModels.Finding {
+		return &lintModels.Finding{
+			Resource: r,
+			Message:  "app has no outbound virtual network integration",
+		}
+	})
+}
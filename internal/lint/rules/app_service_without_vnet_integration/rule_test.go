@@ -0,0 +1,29 @@
+package app_service_without_vnet_integration
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"testing"
+)
+
+func TestAppsWithoutVnetIntegration(t *testing.T) {
+	integrated := &models.Resource{Id: "integrated", Type: types.APP_SERVICE, Name: "integrated", Properties: map[string][]string{"outboundSubnet": {"subnet"}}}
+	app := &models.Resource{Id: "app", Type: types.APP_SERVICE, Name: "app"}
+	function := &models.Resource{Id: "function", Type: types.FUNCTION_APP, Name: "function"}
+	logic := &models.Resource{Id: "logic", Type: types.LOGIC_APP, Name: "logic"}
+	vm := &models.Resource{Id: "vm", Type: types.VIRTUAL_MACHINE, Name: "vm"}
+
+	findings := New().Check([]*models.Resource{integrated, app, function, logic, vm})
+
+	flagged := map[string]bool{}
+
+	for _, finding := range findings {
+		flagged[finding.Resource.Name] = true
+	}
+
+	for name, expected := range map[string]bool{"integrated": false, "app": true, "function": true, "logic": true, "vm": false} {
+		if flagged[name] != expected {
+			t.Errorf("%s: expected finding %v, got %v", name, expected, flagged[name])
+		}
+	}
+}
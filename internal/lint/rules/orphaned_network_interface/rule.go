@@ -0,0 +1,39 @@
+package orphaned_network_interface
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/list"
+)
+
+type rule struct{}
+
+const (
+	ID          = "orphaned-network-interface"
+	DESCRIPTION = "Network interfaces should be attached to a virtual machine, private endpoint or private link service"
+	SEVERITY    = lintModels.SEVERITY_NOTE
+)
+
+func New() *rule {
+	return &rule{}
+}
+
+func (*rule) Check(resources []*models.Resource) []*lintModels.Finding {
+	orphans := list.Filter(resources, func(r *models.Resource) bool {
+		if r.Type != types.NETWORK_INTERFACE {
+			return false
+		}
+
+		_, ok := r.Properties["attachedTo"]
+
+		return !ok
+	})
+
+	return list.Map(orphans, func(r *models.Resource) *lintModels.Finding {
+		return &lintModels.Finding{
+			Resource: r,
+			Message:  "network interface is not attached to anything",
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package orphaned_network_interface
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"testing"
+)
+
+func TestOrphanedNetworkInterface(t *testing.T) {
+	attached := &models.Resource{Id: "attached", Type: types.NETWORK_INTERFACE, Name: "attached", Properties: map[string][]string{"attachedTo": {"vm"}}}
+	orphan := &models.Resource{Id: "orphan", Type: types.NETWORK_INTERFACE, Name: "orphan"}
+
+	findings := New().Check([]*models.Resource{attached, orphan})
+
+	if len(findings) != 1 || findings[0].Resource != orphan {
+		t.Fatalf("expected one finding for the detached network interface, got %v", len(findings))
+	}
+}
@@ -0,0 +1,42 @@
+package orphaned_public_ip
+
+import (
+	"cloudsketch/internal/datastructures/set"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/list"
+)
+
+type rule struct{}
+
+const (
+	ID          = "orphaned-public-ip"
+	DESCRIPTION = "Public IP addresses should be used by a resource"
+	SEVERITY    = lintModels.SEVERITY_NOTE
+)
+
+func New() *rule {
+	return &rule{}
+}
+
+func (*rule) Check(resources []*models.Resource) []*lintModels.Finding {
+	referenced := set.New[string]()
+
+	for _, resource := range resources {
+		for _, dependency := range resource.DependsOn {
+			referenced.Add(dependency.Id)
+		}
+	}
+
+	orphans := list.Filter(resources, func(r *models.Resource) bool {
+		return r.Type == types.PUBLIC_IP_ADDRESS && !referenced.Contains(r.Id)
+	})
+
+	return list.Map(orphans, func(r *models.Resource) *lintModels.Finding {
+		return &lintModels.Finding{
+			Resource: r,
+			Message:  "public IP address is not used by any resource",
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package orphaned_public_ip
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"testing"
+)
+
+func TestOrphanedPublicIp(t *testing.T) {
+	used := &models.Resource{Id: "used", Type: types.PUBLIC_IP_ADDRESS, Name: "used"}
+	orphan := &models.Resource{Id: "orphan", Type: types.PUBLIC_IP_ADDRESS, Name: "orphan"}
+	bastion := &models.Resource{Id: "bastion", Type: types.BASTION, Name: "bastion", DependsOn: []*models.Resource{used}}
+
+	findings := New().Check([]*models.Resource{used, orphan, bastion})
+
+	if len(findings) != 1 || findings[0].Resource != orphan {
+		t.Fatalf("expected one finding for the unused public IP address, got %v", len(findings))
+	}
+}
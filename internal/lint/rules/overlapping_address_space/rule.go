@@ -0,0 +1,30 @@
+package overlapping_address_space
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/ipam"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/list"
+	"fmt"
+)
+
+type rule struct{}
+
+const (
+	ID          = "overlapping-address-space"
+	DESCRIPTION = "Peered virtual networks and virtual networks sharing a hub should not have overlapping address spaces"
+	SEVERITY    = lintModels.SEVERITY_ERROR
+)
+
+func New() *rule {
+	return &rule{}
+}
+
+func (*rule) Check(resources []*models.Resource) []*lintModels.Finding {
+	return list.Map(ipam.Overlaps(resources), func(o *ipam.Overlap) *lintModels.Finding {
+		return &lintModels.Finding{
+			Resource: o.A.Resource,
+			Message:  fmt.Sprintf("address prefix %s overlaps %s of %s (connected via %s)", o.PrefixA, o.PrefixB, o.B.Resource.Name, o.Via),
+		}
+	})
+}
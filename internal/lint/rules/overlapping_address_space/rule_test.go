@@ -0,0 +1,35 @@
+package overlapping_address_space
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/ipam"
+	"testing"
+)
+
+func vnet(id, prefix string, peerings ...string) *models.Resource {
+	return &models.Resource{Id: id, Type: types.VIRTUAL_NETWORK, Name: id, Properties: map[string][]string{
+		ipam.ADDRESS_PREFIXES:        {prefix},
+		ipam.REMOTE_VIRTUAL_NETWORKS: peerings,
+	}}
+}
+
+func TestOverlappingPeerings(t *testing.T) {
+	a := vnet("a", "10.0.0.0/16", "b")
+	b := vnet("b", "10.0.128.0/17", "a")
+
+	findings := New().Check([]*models.Resource{a, b})
+
+	if len(findings) != 1 {
+		t.Fatalf("expected one finding, got %v", len(findings))
+	}
+}
+
+func TestUnconnectedVnetsMayOverlap(t *testing.T) {
+	a := vnet("a", "10.0.0.0/16")
+	b := vnet("b", "10.0.0.0/16")
+
+	if findings := New().Check([]*models.Resource{a, b}); len(findings) != 0 {
+		t.Errorf("expected no findings for virtual networks that cannot reach each other, got %q", findings[0].Message)
+	}
+}
@@ -0,0 +1,109 @@
+package paas_without_private_access
+
+import (
+	"cloudsketch/internal/datastructures/set"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/list"
+	"strings"
+)
+
+type rule struct{}
+
+const (
+	ID          = "paas-without-private-access"
+	DESCRIPTION = "PaaS resources should be reached through a private endpoint, have public network access disabled or network ACLs that deny by default"
+	SEVERITY    = lintModels.SEVERITY_WARNING
+)
+
+var (
+	PAAS_TYPES = []string{
+		types.AI_SERVICES,
+		types.API_MANAGEMENT_SERVICE,
+		types.APP_CONFIGURATION,
+		types.APP_SERVICE,
+		types.CONTAINER_REGISTRY,
+		types.COSMOS,
+		types.DATA_FACTORY,
+		types.FUNCTION_APP,
+		types.KEY_VAULT,
+		types.LOGIC_APP,
+		types.POSTGRES_SQL_SERVER,
+		types.REDIS,
+		types.SEARCH_SERVICE,
+		types.SIGNALR,
+		types.SQL_SERVER,
+		types.STATIC_WEB_APP,
+		types.STORAGE_ACCOUNT,
+	}
+)
+
+func New() *rule {
+	return &rule{}
+}
+
+func (*rule) Check(resources []*models.Resource) []*lintModels.Finding {
+	privateEndpointTargets := set.New[string]()
+
+	for _, resource := range resources {
+		if resource.Type != types.PRIVATE_ENDPOINT {
+			continue
+		}
+
+		attachedTo, ok := resource.Properties["attachedTo"]
+
+		if !ok {
+			continue
+		}
+
+		privateEndpointTargets.Add(attachedTo[0])
+	}
+
+	paasResources := list.Filter(resources, func(r *models.Resource) bool {
+		return list.Contains(PAAS_TYPES, func(t string) bool { return r.Type == t })
+	})
+
+	unrestricted := list.Filter(paasResources, func(r *models.Resource) bool {
+		return !privateEndpointTargets.Contains(r.Id) && !isNetworkRestricted(r)
+	})
+
+	return list.Map(unrestricted, func(r *models.Resource) *lintModels.Finding {
+		_, hasPublicNetworkAccess := r.Properties["publicNetworkAccess"]
+		_, hasNetworkAcls := r.Properties["networkAcls"]
+
+		if !hasPublicNetworkAccess && !hasNetworkAcls {
+			// network access is not collected for every type, or the snapshot predates it
+			return &lintModels.Finding{
+				Resource: r,
+				Severity: lintModels.SEVERITY_NOTE,
+				Message:  "resource has no private endpoint and its network access is unknown",
+			}
+		}
+
+		return &lintModels.Finding{
+			Resource: r,
+			Message:  "resource has no private endpoint, public network access is not disabled and the network ACLs allow all traffic",
+		}
+	})
+}
+
+func isNetworkRestricted(resource *models.Resource) bool {
+	publicNetworkAccess, ok := resource.Properties["publicNetworkAccess"]
+
+	if ok && strings.EqualFold(publicNetworkAccess[0], "disabled") {
+		return true
+	}
+
+	// a firewall that denies by default only lets the allowed networks and addresses in
+	networkAcls, ok := resource.Properties["networkAcls"]
+
+	if ok && strings.EqualFold(networkAcls[0], "deny") {
+		return true
+	}
+
+	// resources injected into a subnet are not reachable from the internet
+	return list.Contains(resource.DependsOn, func(d *models.Resource) bool {
+		return d.Type == types.SUBNET
+	})
+}
@@ -0,0 +1,79 @@
+package paas_without_private_access
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	lintModels "cloudsketch/internal/lint/models"
+	"testing"
+)
+
+func paas(name string, properties map[string][]string) *models.Resource {
+	return &models.Resource{Id: name, Type: types.STORAGE_ACCOUNT, Name: name, Properties: properties}
+}
+
+func TestNetworkAccess(t *testing.T) {
+	for name, tc := range map[string]struct {
+		properties map[string][]string
+		severity   string
+	}{
+		"unknown":               {map[string][]string{}, lintModels.SEVERITY_NOTE},
+		"public":                {map[string][]string{"publicNetworkAccess": {"Enabled"}}, lintModels.SEVERITY_WARNING},
+		"public and acls allow": {map[string][]string{"publicNetworkAccess": {"Enabled"}, "networkAcls": {"Allow"}}, lintModels.SEVERITY_WARNING},
+		"only acls allow":       {map[string][]string{"networkAcls": {"Allow"}}, lintModels.SEVERITY_WARNING},
+		"disabled":              {map[string][]string{"publicNetworkAccess": {"Disabled"}}, ""},
+		"acls deny":             {map[string][]string{"publicNetworkAccess": {"Enabled"}, "networkAcls": {"Deny"}}, ""},
+		"lowercase":             {map[string][]string{"publicNetworkAccess": {"enabled"}, "networkAcls": {"deny"}}, ""},
+	} {
+		findings := New().Check([]*models.Resource{paas(name, tc.properties)})
+
+		if tc.severity == "" {
+			if len(findings) != 0 {
+				t.Errorf("%s: expected no finding, got %q", name, findings[0].Message)
+			}
+
+			continue
+		}
+
+		if len(findings) != 1 {
+			t.Fatalf("%s: expected one finding, got %v", name, len(findings))
+		}
+
+		// rules without an explicit severity get the severity of the rule
+		severity := findings[0].Severity
+
+		if severity == "" {
+			severity = SEVERITY
+		}
+
+		if severity != tc.severity {
+			t.Errorf("%s: expected severity %s, got %s", name, tc.severity, severity)
+		}
+	}
+}
+
+func TestPrivateEndpoint(t *testing.T) {
+	storage := paas("storage", map[string][]string{"publicNetworkAccess": {"Enabled"}})
+	endpoint := &models.Resource{Id: "pe", Type: types.PRIVATE_ENDPOINT, Name: "pe", Properties: map[string][]string{"attachedTo": {storage.Id}}}
+
+	if findings := New().Check([]*models.Resource{storage, endpoint}); len(findings) != 0 {
+		t.Errorf("expected no finding for a resource with a private endpoint, got %q", findings[0].Message)
+	}
+}
+
+func TestSubnetInjection(t *testing.T) {
+	subnet := &models.Resource{Id: "subnet", Type: types.SUBNET, Name: "subnet"}
+	storage := paas("storage", map[string][]string{"publicNetworkAccess": {"Enabled"}})
+	storage.DependsOn = []*models.Resource{subnet}
+
+	if findings := New().Check([]*models.Resource{subnet, storage}); len(findings) != 0 {
+		t.Errorf("expected no finding for a resource injected into a subnet, got %q", findings[0].Message)
+	}
+}
+
+func TestIgnoresOtherTypes(t *testing.T) {
+	vm := &models.Resource{Id: "vm", Type: types.VIRTUAL_MACHINE, Name: "vm"}
+
+	if findings := New().Check([]*models.Resource{vm}); len(findings) != 0 {
+		t.Errorf("expected no finding, got %q", findings[0].Message)
+	}
+}
@@ -0,0 +1,47 @@
+package subnet_without_nsg
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/list"
+	"strings"
+)
+
+type rule struct{}
+
+const (
+	ID          = "subnet-without-nsg"
+	DESCRIPTION = "Subnets should have a network security group attached"
+	SEVERITY    = lintModels.SEVERITY_WARNING
+)
+
+var (
+	// subnets reserved by Azure services that do not support network security groups
+	EXEMPT_SUBNETS = []string{"gatewaysubnet", "azurefirewallsubnet", "azurefirewallmanagementsubnet", "routeserversubnet"}
+)
+
+func New() *rule {
+	return &rule{}
+}
+
+func (*rule) Check(resources []*models.Resource) []*lintModels.Finding {
+	subnets := list.Filter(resources, func(r *models.Resource) bool {
+		return r.Type == types.SUBNET && !list.Contains(EXEMPT_SUBNETS, func(name string) bool {
+			return strings.ToLower(r.Name) == name
+		})
+	})
+
+	subnetsWithoutNsg := list.Filter(subnets, func(subnet *models.Resource) bool {
+		return !list.Contains(subnet.DependsOn, func(d *models.Resource) bool {
+			return d.Type == types.NETWORK_SECURITY_GROUP
+		})
+	})
+
+	return list.Map(subnetsWithoutNsg, func(subnet *models.Resource) *lintModels.Finding {
+		return &lintModels.Finding{
+			Resource: subnet,
+			Message:  "subnet has no network security group attached",
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package subnet_without_nsg
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"testing"
+)
+
+func TestSubnetWithoutNsg(t *testing.T) {
+	nsg := &models.Resource{Id: "nsg", Type: types.NETWORK_SECURITY_GROUP, Name: "nsg"}
+	protected := &models.Resource{Id: "protected", Type: types.SUBNET, Name: "protected", DependsOn: []*models.Resource{nsg}}
+	open := &models.Resource{Id: "open", Type: types.SUBNET, Name: "open"}
+
+	findings := New().Check([]*models.Resource{nsg, protected, open})
+
+	if len(findings) != 1 || findings[0].Resource != open {
+		t.Fatalf("expected one finding for the subnet without a network security group, got %v", len(findings))
+	}
+}
+
+func TestExemptSubnets(t *testing.T) {
+	resources := []*models.Resource{
+		{Id: "gateway", Type: types.SUBNET, Name: "GatewaySubnet"},
+		{Id: "firewall", Type: types.SUBNET, Name: "AzureFirewallSubnet"},
+		{Id: "firewall-management", Type: types.SUBNET, Name: "AzureFirewallManagementSubnet"},
+		{Id: "route-server", Type: types.SUBNET, Name: "RouteServerSubnet"},
+	}
+
+	if findings := New().Check(resources); len(findings) != 0 {
+		t.Errorf("expected no findings for subnets that do not support network security groups, got %q", findings[0].Resource.Name)
+	}
+}
@@ -0,0 +1,50 @@
+package unlinked_private_dns_zone
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/list"
+	"strconv"
+)
+
+type rule struct{}
+
+const (
+	ID          = "unlinked-private-dns-zone"
+	DESCRIPTION = "Private DNS zones should be linked to at least one virtual network"
+	SEVERITY    = lintModels.SEVERITY_WARNING
+)
+
+func New() *rule {
+	return &rule{}
+}
+
+func (*rule) Check(resources []*models.Resource) []*lintModels.Finding {
+	unlinked := list.Filter(resources, func(r *models.Resource) bool {
+		return r.Type == types.PRIVATE_DNS_ZONE && !isLinked(r)
+	})
+
+	return list.Map(unlinked, func(r *models.Resource) *lintModels.Finding {
+		return &lintModels.Finding{
+			Resource: r,
+			Message:  "private DNS zone is not linked to any virtual network",
+		}
+	})
+}
+
+func isLinked(zone *models.Resource) bool {
+	// links to virtual networks outside the snapshot are only counted, they are not dependencies of the zone
+	if links, ok := zone.Properties["virtualNetworkLinks"]; ok {
+		count, err := strconv.Atoi(links[0])
+
+		if err == nil && count > 0 {
+			return true
+		}
+	}
+
+	// virtual network links are stored as dependencies of the zone
+	return list.Contains(zone.DependsOn, func(d *models.Resource) bool {
+		return d.Type == types.VIRTUAL_NETWORK
+	})
+}
@@ -0,0 +1,30 @@
+package unlinked_private_dns_zone
+
+import (
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"testing"
+)
+
+func TestUnlinkedPrivateDnsZone(t *testing.T) {
+	vnet := &models.Resource{Id: "vnet", Type: types.VIRTUAL_NETWORK, Name: "vnet"}
+	linked := &models.Resource{Id: "linked", Type: types.PRIVATE_DNS_ZONE, Name: "linked", DependsOn: []*models.Resource{vnet}}
+	// links to virtual networks outside of the snapshot are only counted
+	linkedOutside := &models.Resource{Id: "outside", Type: types.PRIVATE_DNS_ZONE, Name: "outside", Properties: map[string][]string{"virtualNetworkLinks": {"2"}}}
+	noLinks := &models.Resource{Id: "none", Type: types.PRIVATE_DNS_ZONE, Name: "none", Properties: map[string][]string{"virtualNetworkLinks": {"0"}}}
+	unlinked := &models.Resource{Id: "unlinked", Type: types.PRIVATE_DNS_ZONE, Name: "unlinked"}
+
+	findings := New().Check([]*models.Resource{vnet, linked, linkedOutside, noLinks, unlinked})
+
+	flagged := map[string]bool{}
+
+	for _, finding := range findings {
+		flagged[finding.Resource.Name] = true
+	}
+
+	for name, expected := range map[string]bool{"linked": false, "outside": false, "none": true, "unlinked": true} {
+		if flagged[name] != expected {
+			t.Errorf("%s: expected finding %v, got %v", name, expected, flagged[name])
+		}
+	}
+}
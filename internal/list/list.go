@@ -57,6 +57,16 @@ func FirstOrDefault[T any](v []T, def T, f func(T) bool) T {
 	return def
 }
 
+func FirstIndex[T any](v []T, f func(T) bool) int {
+	for i, e := range v {
+		if f(e) {
+			return i
+		}
+	}
+
+	return -1
+}
+
 func Fold[T, K any](v []T, acc K, f func(T, K) K) K {
 	for _, i := range v {
 		acc = f(i, acc)
@@ -3,22 +3,21 @@ package marshall
 import (
 	"encoding/json"
 	"errors"
-	"log"
 	"os"
 )
 
-func UnmarshalIfExists[T any](path string) (*T, bool) {
+func UnmarshalIfExists[T any](path string) (*T, bool, error) {
 	if !FileExists(path) {
-		return nil, false
+		return nil, false, nil
 	}
 
 	result, err := UnmarshallResources[T](path)
 
 	if err != nil {
-		panic(err)
+		return nil, false, err
 	}
 
-	return result, true
+	return result, true, nil
 }
 
 func FileExists(file string) bool {
@@ -31,13 +30,13 @@ func MarshallResources[T any](path string, r T) error {
 	bytes, err := json.MarshalIndent(r, "", "\t")
 
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	f, err := os.Create(path)
 
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	defer f.Close()
@@ -51,7 +50,7 @@ func UnmarshallResources[T any](file string) (*T, error) {
 	bytes, err := os.ReadFile(file)
 
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	var t *T
@@ -1,9 +1,18 @@
 package context
 
-import "github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+)
 
 type Context struct {
-	Credentials                                                           *azidentity.DefaultAzureCredential
+	// cancels outstanding requests to Azure
+	Context     context.Context
+	Credentials azcore.TokenCredential
+	// endpoint and transport of the ARM clients. nil uses the public cloud
+	ClientOptions                                                         *arm.ClientOptions
 	SubscriptionId, ResourceGroupName, ResourceName, ResourceId, TenantId string
 }
 
@@ -4,7 +4,6 @@ import (
 	"cloudsketch/internal/list"
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
-	"context"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement/v3"
@@ -17,7 +16,7 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	clientFactory, err := armapimanagement.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, nil)
+	clientFactory, err := armapimanagement.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -25,7 +24,7 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 
 	client := clientFactory.NewServiceClient()
 
-	apim, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	apim, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
@@ -36,11 +35,18 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 	dependsOn = append(dependsOn, strings.ToLower(*apim.Properties.PublicIPAddressID))
 	dependsOn = append(dependsOn, strings.ToLower(*apim.Properties.VirtualNetworkConfiguration.SubnetResourceID))
 
+	properties := map[string][]string{}
+
+	if apim.Properties.PublicNetworkAccess != nil {
+		properties["publicNetworkAccess"] = []string{string(*apim.Properties.PublicNetworkAccess)}
+	}
+
 	resources := []*models.Resource{&models.Resource{
-		Id:        ctx.ResourceId,
-		Name:      ctx.ResourceName,
-		Type:      *apim.Type,
-		DependsOn: dependsOn,
+		Id:         ctx.ResourceId,
+		Name:       ctx.ResourceName,
+		Type:       *apim.Type,
+		DependsOn:  dependsOn,
+		Properties: properties,
 	}}
 
 	apis, err := getAPIs(clientFactory, ctx)
@@ -61,7 +67,7 @@ func getAPIs(clientFactory *armapimanagement.ClientFactory, ctx *azContext.Conte
 
 	var apis []*armapimanagement.APIContract
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -3,7 +3,6 @@ package application_gateway
 import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
-	"context"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
@@ -16,13 +15,13 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	client, err := armnetwork.NewApplicationGatewaysClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armnetwork.NewApplicationGatewaysClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
 	}
 
-	agw, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	agw, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
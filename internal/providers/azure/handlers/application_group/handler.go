@@ -3,7 +3,6 @@ package application_group
 import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
-	"context"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/desktopvirtualization/armdesktopvirtualization/v2"
 )
@@ -16,13 +15,13 @@ func New() *handler {
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
 
-	client, err := armdesktopvirtualization.NewApplicationGroupsClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armdesktopvirtualization.NewApplicationGroupsClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
 	}
 
-	applicationGroup, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	applicationGroup, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
@@ -4,7 +4,6 @@ import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
 	"cloudsketch/internal/providers/azure/types"
-	"context"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/applicationinsights/armapplicationinsights"
 )
@@ -16,13 +15,13 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	client, err := armapplicationinsights.NewComponentsClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armapplicationinsights.NewComponentsClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
 	}
 
-	ai, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	ai, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
@@ -3,7 +3,6 @@ package bastion
 import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
-	"context"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 )
@@ -15,13 +14,13 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	client, err := armnetwork.NewBastionHostsClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armnetwork.NewBastionHostsClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
 	}
 
-	bastion, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	bastion, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
@@ -3,7 +3,6 @@ package container_app
 import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
-	"context"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
@@ -16,13 +15,13 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	client, err := armappcontainers.NewContainerAppsClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armappcontainers.NewContainerAppsClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
 	}
 
-	ca, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	ca, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
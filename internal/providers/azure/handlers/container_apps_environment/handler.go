@@ -5,8 +5,6 @@ import (
 	"cloudsketch/internal/providers/azure/models"
 	"strings"
 
-	"context"
-
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
 )
 
@@ -17,13 +15,13 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	client, err := armappcontainers.NewManagedEnvironmentsClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armappcontainers.NewManagedEnvironmentsClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
 	}
 
-	cae, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	cae, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
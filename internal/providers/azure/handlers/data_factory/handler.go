@@ -4,7 +4,6 @@ import (
 	"cloudsketch/internal/list"
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
-	"context"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/datafactory/armdatafactory/v9"
 )
@@ -16,7 +15,7 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	clientFactory, err := armdatafactory.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, nil)
+	clientFactory, err := armdatafactory.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -24,16 +23,23 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 
 	client := clientFactory.NewFactoriesClient()
 
-	adf, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	adf, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
+	properties := map[string][]string{}
+
+	if adf.Properties != nil && adf.Properties.PublicNetworkAccess != nil {
+		properties["publicNetworkAccess"] = []string{string(*adf.Properties.PublicNetworkAccess)}
+	}
+
 	resource := &models.Resource{
-		Id:   *adf.ID,
-		Name: *adf.Name,
-		Type: *adf.Type,
+		Id:         *adf.ID,
+		Name:       *adf.Name,
+		Type:       *adf.Type,
+		Properties: properties,
 	}
 
 	resources := []*models.Resource{resource}
@@ -70,7 +76,7 @@ func getManagedVirtualNetworks(clientFactory *armdatafactory.ClientFactory, ctx
 
 	var networks []*armdatafactory.ManagedVirtualNetworkResource
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -90,7 +96,7 @@ func getManagedPrivateEndpoints(clientFactory *armdatafactory.ClientFactory, ctx
 
 	var endpoints []*armdatafactory.ManagedPrivateEndpointResource
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -119,7 +125,7 @@ func getIntegrationRuntimes(clientFactory *armdatafactory.ClientFactory, ctx *az
 
 	var integration_runtimes []*armdatafactory.IntegrationRuntimeResource
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -4,7 +4,6 @@ import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
 	"cloudsketch/internal/providers/azure/types"
-	"context"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/desktopvirtualization/armdesktopvirtualization/v2"
 )
@@ -16,7 +15,7 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	client, err := armdesktopvirtualization.NewSessionHostsClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armdesktopvirtualization.NewSessionHostsClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -27,7 +26,7 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 	var sessionHosts []*armdesktopvirtualization.SessionHost
 
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
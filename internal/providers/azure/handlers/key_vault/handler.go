@@ -2,21 +2,26 @@ package key_vault
 
 import (
 	azContext "cloudsketch/internal/providers/azure/context"
+	"cloudsketch/internal/providers/azure/handlers/network_access"
 	"cloudsketch/internal/providers/azure/models"
 	"cloudsketch/internal/providers/azure/types"
-	"context"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
 )
 
 type handler struct{}
 
+const (
+	// the key vault SDK is not used, the network access is read through the generic resources API
+	API_VERSION = "2023-07-01"
+)
+
 func New() *handler {
 	return &handler{}
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	diagnosticsClient, err := armmonitor.NewDiagnosticSettingsClient(ctx.Credentials, nil)
+	diagnosticsClient, err := armmonitor.NewDiagnosticSettingsClient(ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -26,7 +31,7 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 
 	var resources []*armmonitor.DiagnosticSettingsResource
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -47,11 +52,18 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 		}
 	}
 
+	properties, err := network_access.Get(ctx, API_VERSION)
+
+	if err != nil {
+		return nil, err
+	}
+
 	resource := &models.Resource{
-		Id:        ctx.ResourceId,
-		Name:      ctx.ResourceName,
-		Type:      types.KEY_VAULT,
-		DependsOn: dependsOn,
+		Id:         ctx.ResourceId,
+		Name:       ctx.ResourceName,
+		Type:       types.KEY_VAULT,
+		DependsOn:  dependsOn,
+		Properties: properties,
 	}
 
 	return []*models.Resource{resource}, nil
@@ -4,7 +4,6 @@ import (
 	"cloudsketch/internal/list"
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
-	"context"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
@@ -17,7 +16,7 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	clientFactory, err := armnetwork.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, nil)
+	clientFactory, err := armnetwork.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -25,7 +24,7 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 
 	client := clientFactory.NewLoadBalancersClient()
 
-	lb, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	lb, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
@@ -65,7 +64,7 @@ func getFrontends(clientFactory *armnetwork.ClientFactory, ctx *azContext.Contex
 
 	var frontendConfiguration []*armnetwork.FrontendIPConfiguration
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -100,7 +99,7 @@ func getBackendPools(clientFactory *armnetwork.ClientFactory, ctx *azContext.Con
 
 	var pools []*armnetwork.BackendAddressPool
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,66 @@
+package network_access
+
+import (
+	"cloudsketch/internal/list"
+	azContext "cloudsketch/internal/providers/azure/context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources/v2"
+)
+
+var (
+	// PaaS types name their network ACLs differently, the default action is read from whichever is present
+	NETWORK_ACL_PROPERTIES = []string{"networkacls", "networkruleset"}
+)
+
+// Get reads the network access of a resource through the generic resources API, for types that have no SDK in use
+func Get(ctx *azContext.Context, apiVersion string) (map[string][]string, error) {
+	client, err := armresources.NewClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := client.GetByID(ctx.Context, ctx.ResourceId, apiVersion, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	properties, _ := resource.Properties.(map[string]any)
+
+	return Properties(properties), nil
+}
+
+// Properties maps the public network access and the default action of the network ACLs to resource properties
+func Properties(properties map[string]any) map[string][]string {
+	result := map[string][]string{}
+
+	for key, value := range properties {
+		lowerKey := strings.ToLower(key)
+
+		if lowerKey == "publicnetworkaccess" {
+			if s, ok := value.(string); ok && s != "" {
+				result["publicNetworkAccess"] = []string{s}
+			}
+
+			continue
+		}
+
+		if !list.Contains(NETWORK_ACL_PROPERTIES, func(name string) bool { return name == lowerKey }) {
+			continue
+		}
+
+		acls, ok := value.(map[string]any)
+
+		if !ok {
+			continue
+		}
+
+		if defaultAction, ok := acls["defaultAction"].(string); ok && defaultAction != "" {
+			result["networkAcls"] = []string{defaultAction}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,41 @@
+package paas
+
+import (
+	azContext "cloudsketch/internal/providers/azure/context"
+	"cloudsketch/internal/providers/azure/handlers/network_access"
+	"cloudsketch/internal/providers/azure/models"
+)
+
+// handler collects the network access of PaaS types that are otherwise added as listed
+type handler struct {
+	resourceType string
+	apiVersion   string
+}
+
+func New(resourceType, apiVersion string) *handler {
+	return &handler{
+		resourceType: resourceType,
+		apiVersion:   apiVersion,
+	}
+}
+
+func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
+	properties, err := network_access.Get(ctx, h.apiVersion)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &models.Resource{
+		Id:         ctx.ResourceId,
+		Name:       ctx.ResourceName,
+		Type:       h.resourceType,
+		Properties: properties,
+	}
+
+	return []*models.Resource{resource}, nil
+}
+
+func (h *handler) PostProcess(resource *models.Resource, resources []*models.Resource) {
+
+}
@@ -3,7 +3,6 @@ package postgres_flexible_server
 import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
-	"context"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/postgresql/armpostgresqlflexibleservers/v5"
@@ -16,13 +15,13 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	client, err := armpostgresqlflexibleservers.NewServersClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armpostgresqlflexibleservers.NewServersClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
 	}
 
-	pfsql, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	pfsql, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
@@ -38,11 +37,18 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 		dependsOn = append(dependsOn, strings.ToLower(*pfsql.Properties.Network.PrivateDNSZoneArmResourceID))
 	}
 
+	properties := map[string][]string{}
+
+	if pfsql.Properties.Network.PublicNetworkAccess != nil {
+		properties["publicNetworkAccess"] = []string{string(*pfsql.Properties.Network.PublicNetworkAccess)}
+	}
+
 	resource := &models.Resource{
-		Id:        ctx.ResourceId,
-		Name:      ctx.ResourceName,
-		Type:      *pfsql.Type,
-		DependsOn: dependsOn,
+		Id:         ctx.ResourceId,
+		Name:       ctx.ResourceName,
+		Type:       *pfsql.Type,
+		DependsOn:  dependsOn,
+		Properties: properties,
 	}
 
 	return []*models.Resource{resource}, nil
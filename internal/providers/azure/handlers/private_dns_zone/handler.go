@@ -5,7 +5,7 @@ import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
 	"cloudsketch/internal/providers/azure/types"
-	"context"
+	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
@@ -18,13 +18,13 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	clientFactory, err := armprivatedns.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, nil)
+	clientFactory, err := armprivatedns.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
 	}
 
-	dnsZone, err := clientFactory.NewPrivateZonesClient().Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	dnsZone, err := clientFactory.NewPrivateZonesClient().Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
@@ -54,6 +54,10 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 	}
 
 	resource.DependsOn = append(resource.DependsOn, vnetLinks...)
+	// links to virtual networks of other subscriptions are removed from the dependencies later, the count keeps them
+	resource.Properties = map[string][]string{
+		"virtualNetworkLinks": {strconv.Itoa(len(vnetLinks))},
+	}
 
 	return resources, nil
 }
@@ -63,7 +67,7 @@ func getVnetLinks(clientFactory *armprivatedns.ClientFactory, ctx *azContext.Con
 
 	var links []*armprivatedns.VirtualNetworkLink
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -85,7 +89,7 @@ func getRecordSet(clientFactory *armprivatedns.ClientFactory, ctx *azContext.Con
 
 	var records []*armprivatedns.RecordSet
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -1,7 +1,8 @@
 package resource_group
 
 import (
-	"context"
+	"fmt"
+	"sort"
 
 	"cloudsketch/internal/list"
 	azContext "cloudsketch/internal/providers/azure/context"
@@ -17,7 +18,7 @@ func New() *handler {
 }
 
 func (*handler) Handle(ctx *azContext.Context) ([]*models.Resource, error) {
-	client, err := armresources.NewResourceGroupsClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armresources.NewResourceGroupsClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -28,7 +29,7 @@ func (*handler) Handle(ctx *azContext.Context) ([]*models.Resource, error) {
 	var resourceGroups []*armresources.ResourceGroup
 
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -37,7 +38,7 @@ func (*handler) Handle(ctx *azContext.Context) ([]*models.Resource, error) {
 		}
 	}
 
-	resourceClient, err := armresources.NewClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	resourceClient, err := armresources.NewClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -46,7 +47,7 @@ func (*handler) Handle(ctx *azContext.Context) ([]*models.Resource, error) {
 	resources := []*models.Resource{}
 
 	for _, resourceGroup := range resourceGroups {
-		r, err := GetResourcesInResourceGroup(resourceClient, *resourceGroup.Name)
+		r, err := GetResourcesInResourceGroup(ctx, resourceClient, *resourceGroup.Name)
 
 		if err != nil {
 			return nil, err
@@ -58,12 +59,12 @@ func (*handler) Handle(ctx *azContext.Context) ([]*models.Resource, error) {
 	return resources, nil
 }
 
-func GetResourcesInResourceGroup(client *armresources.Client, resourceGroup string) ([]*models.Resource, error) {
+func GetResourcesInResourceGroup(ctx *azContext.Context, client *armresources.Client, resourceGroup string) ([]*models.Resource, error) {
 	pager := client.NewListByResourceGroupPager(resourceGroup, nil)
 
 	var resources []*armresources.GenericResourceExpanded
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx.Context)
 		if err != nil {
 			return nil, err
 		}
@@ -74,13 +75,37 @@ func GetResourcesInResourceGroup(client *armresources.Client, resourceGroup stri
 	}
 
 	azResources := list.Map(resources, func(resource *armresources.GenericResourceExpanded) *models.Resource {
+		properties := map[string][]string{}
+
+		if len(resource.Tags) > 0 {
+			properties["tags"] = mapTags(resource.Tags)
+		}
+
 		return &models.Resource{
 			Id:            *resource.ID,
 			Name:          *resource.Name,
 			Type:          *resource.Type,
 			ResourceGroup: resourceGroup,
+			Properties:    properties,
 		}
 	})
 
 	return azResources, nil
 }
+
+func mapTags(tags map[string]*string) []string {
+	result := []string{}
+
+	for k, v := range tags {
+		if v == nil {
+			result = append(result, k)
+			continue
+		}
+
+		result = append(result, fmt.Sprintf("%s=%s", k, *v))
+	}
+
+	sort.Strings(result)
+
+	return result
+}
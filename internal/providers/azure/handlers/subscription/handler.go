@@ -4,7 +4,8 @@ import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 )
 
@@ -14,14 +15,14 @@ func New() *handler {
 	return &handler{}
 }
 
-func (*handler) Handle(subscriptionId string, credentials *azidentity.DefaultAzureCredential) (*azContext.SubscriptionContext, error) {
-	clientFactory, err := armsubscriptions.NewClientFactory(credentials, nil)
+func (*handler) Handle(ctx context.Context, subscriptionId string, credentials azcore.TokenCredential, options *arm.ClientOptions) (*azContext.SubscriptionContext, error) {
+	clientFactory, err := armsubscriptions.NewClientFactory(credentials, options)
 
 	if err != nil {
 		return nil, err
 	}
 
-	subscription, err := clientFactory.NewClient().Get(context.Background(), subscriptionId, nil)
+	subscription, err := clientFactory.NewClient().Get(ctx, subscriptionId, nil)
 
 	if err != nil {
 		return nil, err
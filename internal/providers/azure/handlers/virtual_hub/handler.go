@@ -3,7 +3,7 @@ package virtual_hub
 import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
-	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 )
@@ -15,7 +15,7 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	clientFactory, err := armnetwork.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, nil)
+	clientFactory, err := armnetwork.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -23,17 +23,31 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 
 	client := clientFactory.NewVirtualHubsClient()
 
-	vhub, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	vhub, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
+	remoteVirtualNetworks, err := getConnectedVirtualNetworks(clientFactory, ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	properties := map[string][]string{}
+
+	// connected virtual networks are not dependencies. They are only needed to detect overlapping address spaces
+	if len(remoteVirtualNetworks) > 0 {
+		properties["remoteVirtualNetworks"] = remoteVirtualNetworks
+	}
+
 	resource := &models.Resource{
-		Id:        ctx.ResourceId,
-		Name:      ctx.ResourceName,
-		Type:      *vhub.Type,
-		DependsOn: []string{*vhub.Properties.VirtualWan.ID},
+		Id:         ctx.ResourceId,
+		Name:       ctx.ResourceName,
+		Type:       *vhub.Type,
+		DependsOn:  []string{*vhub.Properties.VirtualWan.ID},
+		Properties: properties,
 	}
 
 	resources := []*models.Resource{resource}
@@ -41,6 +55,32 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 	return resources, nil
 }
 
+func getConnectedVirtualNetworks(clientFactory *armnetwork.ClientFactory, ctx *azContext.Context) ([]string, error) {
+	client := clientFactory.NewHubVirtualNetworkConnectionsClient()
+
+	pager := client.NewListPager(ctx.ResourceGroupName, ctx.ResourceName, nil)
+
+	remoteVirtualNetworks := []string{}
+
+	for pager.More() {
+		resp, err := pager.NextPage(ctx.Context)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, connection := range resp.Value {
+			if connection.Properties == nil || connection.Properties.RemoteVirtualNetwork == nil {
+				continue
+			}
+
+			remoteVirtualNetworks = append(remoteVirtualNetworks, strings.ToLower(*connection.Properties.RemoteVirtualNetwork.ID))
+		}
+	}
+
+	return remoteVirtualNetworks, nil
+}
+
 func (h *handler) PostProcess(resource *models.Resource, resources []*models.Resource) {
 
 }
@@ -5,7 +5,6 @@ import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
 	"cloudsketch/internal/providers/azure/types"
-	"context"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
@@ -18,7 +17,7 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	clientFactory, err := armnetwork.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, nil)
+	clientFactory, err := armnetwork.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -26,7 +25,7 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 
 	client := clientFactory.NewVirtualNetworksClient()
 
-	vnet, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	vnet, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
@@ -49,15 +48,24 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 }
 
 func mapVirtualNetworkResource(vnet *armnetwork.VirtualNetworksClientGetResponse, ctx *azContext.Context) (*models.Resource, error) {
-	addressPrefixes := vnet.Properties.AddressSpace.AddressPrefixes
+	addressPrefixes := list.Map(vnet.Properties.AddressSpace.AddressPrefixes, func(p *string) string { return *p })
 
-	properties := map[string][]string{}
+	properties := map[string][]string{
+		"addressPrefixes": addressPrefixes,
+	}
 
 	// virtual networks can have multiple address ranges. If this is the case hide the size
 	if len(addressPrefixes) == 1 {
-		addressPrefix := strings.Split(*addressPrefixes[0], "/")[1]
+		properties["size"] = []string{prefixLength(addressPrefixes[0])}
+	}
+
+	// peerings are not dependencies. Two peered virtual networks reference each other which would form a cycle
+	remoteVirtualNetworks := list.Map(vnet.Properties.VirtualNetworkPeerings, func(peering *armnetwork.VirtualNetworkPeering) string {
+		return strings.ToLower(*peering.Properties.RemoteVirtualNetwork.ID)
+	})
 
-		properties["size"] = []string{addressPrefix}
+	if len(remoteVirtualNetworks) > 0 {
+		properties["remoteVirtualNetworks"] = remoteVirtualNetworks
 	}
 
 	resource := &models.Resource{
@@ -86,10 +94,19 @@ func mapSubnetResources(subnets []*armnetwork.Subnet, vnetId string) ([]*models.
 			dependsOn = append(dependsOn, strings.ToLower(*nsg.ID))
 		}
 
-		addressPrefix := strings.Split(*subnet.Properties.AddressPrefix, "/")[1]
+		addressPrefixes := list.Map(subnet.Properties.AddressPrefixes, func(p *string) string { return *p })
+
+		// subnets with a single range only populate AddressPrefix
+		if subnet.Properties.AddressPrefix != nil {
+			addressPrefixes = []string{*subnet.Properties.AddressPrefix}
+		}
 
 		properties := map[string][]string{
-			"size": {addressPrefix},
+			"addressPrefixes": addressPrefixes,
+		}
+
+		if len(addressPrefixes) == 1 {
+			properties["size"] = []string{prefixLength(addressPrefixes[0])}
 		}
 
 		snet := &models.Resource{
@@ -106,6 +123,12 @@ func mapSubnetResources(subnets []*armnetwork.Subnet, vnetId string) ([]*models.
 	return resources, nil
 }
 
+func prefixLength(addressPrefix string) string {
+	_, length, _ := strings.Cut(addressPrefix, "/")
+
+	return length
+}
+
 func (h *handler) PostProcess(resource *models.Resource, resources []*models.Resource) {
 
 }
@@ -5,7 +5,6 @@ import (
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/models"
 	"cloudsketch/internal/providers/azure/types"
-	"context"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v4"
@@ -36,19 +35,19 @@ func New() *handler {
 }
 
 func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error) {
-	client, err := armappservice.NewWebAppsClient(ctx.SubscriptionId, ctx.Credentials, nil)
+	client, err := armappservice.NewWebAppsClient(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
 	}
 
-	app, err := client.Get(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	app, err := client.Get(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	config, err := client.GetConfiguration(context.Background(), ctx.ResourceGroupName, ctx.ResourceName, nil)
+	config, err := client.GetConfiguration(ctx.Context, ctx.ResourceGroupName, ctx.ResourceName, nil)
 
 	if err != nil {
 		return nil, err
@@ -88,6 +87,19 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 		properties["outboundSubnet"] = []string{strings.ToLower(*outboundSubnetId)}
 	}
 
+	publicNetworkAccess := app.Properties.PublicNetworkAccess
+
+	if publicNetworkAccess != nil && *publicNetworkAccess != "" {
+		properties["publicNetworkAccess"] = []string{*publicNetworkAccess}
+	}
+
+	// access restrictions are the network ACLs of app services
+	defaultAction := config.Properties.IPSecurityRestrictionsDefaultAction
+
+	if defaultAction != nil {
+		properties["networkAcls"] = []string{string(*defaultAction)}
+	}
+
 	planId := app.Properties.ServerFarmID
 	dependsOn = append(dependsOn, *planId)
 
@@ -103,7 +115,7 @@ func (h *handler) GetResource(ctx *azContext.Context) ([]*models.Resource, error
 }
 
 func getResourceReferencesInTags(ctx *azContext.Context) ([]string, error) {
-	clientFactory, err := armresources.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, nil)
+	clientFactory, err := armresources.NewClientFactory(ctx.SubscriptionId, ctx.Credentials, ctx.ClientOptions)
 
 	if err != nil {
 		return nil, err
@@ -111,7 +123,7 @@ func getResourceReferencesInTags(ctx *azContext.Context) ([]string, error) {
 
 	client := clientFactory.NewTagsClient()
 
-	tags, err := client.GetAtScope(context.Background(), ctx.ResourceId, nil)
+	tags, err := client.GetAtScope(ctx.Context, ctx.ResourceId, nil)
 
 	if err != nil {
 		return nil, err
@@ -4,7 +4,6 @@ import (
 	"cloudsketch/internal/concurrency"
 	"cloudsketch/internal/datastructures/set"
 	"cloudsketch/internal/list"
-	"cloudsketch/internal/marshall"
 	"cloudsketch/internal/providers"
 	azContext "cloudsketch/internal/providers/azure/context"
 	"cloudsketch/internal/providers/azure/handlers/api_management_service"
@@ -22,6 +21,7 @@ import (
 	"cloudsketch/internal/providers/azure/handlers/load_balancer"
 	"cloudsketch/internal/providers/azure/handlers/nat_gateway"
 	"cloudsketch/internal/providers/azure/handlers/network_interface"
+	"cloudsketch/internal/providers/azure/handlers/paas"
 	"cloudsketch/internal/providers/azure/handlers/postgres_flexible_server"
 	"cloudsketch/internal/providers/azure/handlers/private_dns_resolver"
 	"cloudsketch/internal/providers/azure/handlers/private_dns_zone"
@@ -37,12 +37,15 @@ import (
 	"cloudsketch/internal/providers/azure/handlers/web_sites"
 	"cloudsketch/internal/providers/azure/models"
 	"cloudsketch/internal/providers/azure/types"
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	domainTypes "cloudsketch/internal/frontends/types"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 )
 
@@ -53,17 +56,21 @@ type handler interface {
 
 var (
 	handlers map[string]handler = map[string]handler{
+		types.AI_SERVICES:                paas.New(types.AI_SERVICES, "2024-10-01"),
 		types.API_MANAGEMENT_SERVICE:     api_management_service.New(),
 		types.APPLICATION_GATEWAY:        application_gateway.New(),
 		types.APPLICATION_GROUP:          application_group.New(),
 		types.APPLICATION_INSIGHTS:       application_insights.New(),
+		types.APP_CONFIGURATION:          paas.New(types.APP_CONFIGURATION, "2024-05-01"),
+		types.BASTION:                    bastion.New(),
+		types.CONTAINER_APP:              container_app.New(),
+		types.CONTAINER_APPS_ENVIRONMENT: container_apps_environment.New(),
+		types.CONTAINER_REGISTRY:         paas.New(types.CONTAINER_REGISTRY, "2023-07-01"),
+		types.COSMOS:                     paas.New(types.COSMOS, "2024-11-15"),
 		types.DATA_FACTORY:               data_factory.New(),
 		types.EXPRESS_ROUTE_CIRCUIT:      express_route_circuit.New(),
 		types.EXPRESS_ROUTE_GATEWAY:      express_route_gateway.New(),
 		types.HOST_POOL:                  host_pool.New(),
-		types.BASTION:                    bastion.New(),
-		types.CONTAINER_APP:              container_app.New(),
-		types.CONTAINER_APPS_ENVIRONMENT: container_apps_environment.New(),
 		types.KEY_VAULT:                  key_vault.New(),
 		types.LOAD_BALANCER:              load_balancer.New(),
 		types.NAT_GATEWAY:                nat_gateway.New(),
@@ -73,6 +80,12 @@ var (
 		types.PRIVATE_DNS_ZONE:           private_dns_zone.New(),
 		types.PRIVATE_ENDPOINT:           private_endpoint.New(),
 		types.PRIVATE_LINK_SERVICE:       private_link_service.New(),
+		types.REDIS:                      paas.New(types.REDIS, "2024-03-01"),
+		types.SEARCH_SERVICE:             paas.New(types.SEARCH_SERVICE, "2023-11-01"),
+		types.SIGNALR:                    paas.New(types.SIGNALR, "2024-03-01"),
+		types.SQL_SERVER:                 paas.New(types.SQL_SERVER, "2023-08-01"),
+		types.STATIC_WEB_APP:             paas.New(types.STATIC_WEB_APP, "2024-04-01"),
+		types.STORAGE_ACCOUNT:            paas.New(types.STORAGE_ACCOUNT, "2023-05-01"),
 		types.VIRTUAL_HUB:                virtual_hub.New(),
 		types.VIRTUAL_MACHINE:            virtual_machine.New(),
 		types.VIRTUAL_MACHINE_SCALE_SET:  virtual_machine_scale_set.New(),
@@ -82,58 +95,77 @@ var (
 	}
 )
 
-type azureProvider struct{}
+// Options configure how the provider reaches Azure. Without credentials DefaultAzureCredential is used, without client options the public cloud
+type Options struct {
+	Credentials   azcore.TokenCredential
+	ClientOptions *arm.ClientOptions
+}
+
+type azureProvider struct {
+	options *Options
+}
+
+func NewProvider(options *Options) *azureProvider {
+	if options == nil {
+		options = &Options{}
+	}
 
-func NewProvider() *azureProvider {
-	return &azureProvider{}
+	return &azureProvider{
+		options: options,
+	}
 }
 
-func (h *azureProvider) FetchResources(subscriptionId string) ([]*providers.Resource, string, error) {
-	credentials, err := azidentity.NewDefaultAzureCredential(nil)
+func (h *azureProvider) FetchResources(ctx context.Context, subscriptionId string) ([]*providers.Resource, *providers.Metadata, error) {
+	credentials, err := h.credentials()
 
 	if err != nil {
-		return nil, "", fmt.Errorf("authentication failure: %+v", err)
+		return nil, nil, fmt.Errorf("authentication failure: %+v", err)
 	}
 
-	subscription, err := subscription.New().Handle(subscriptionId, credentials)
+	subscription, err := subscription.New().Handle(ctx, subscriptionId, credentials, h.options.ClientOptions)
 
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
-	ctx := &azContext.Context{
+	azCtx := &azContext.Context{
+		Context:        ctx,
 		SubscriptionId: subscription.Id,
 		Credentials:    credentials,
+		ClientOptions:  h.options.ClientOptions,
 		TenantId:       subscription.TenantId,
 	}
 
-	filename := fmt.Sprintf("%s_%s", subscription.Name, subscription.Id)
-	filenameWithSuffix := fmt.Sprintf("%s.json", filename)
-
-	cachedResources, ok := marshall.UnmarshalIfExists[[]*models.Resource](filenameWithSuffix)
-
-	if ok {
-		log.Printf("using existing file %s\n", filenameWithSuffix)
-
-		return mapToProviderModel(*cachedResources), filename, nil
+	metadata := &providers.Metadata{
+		Name:     fmt.Sprintf("%s_%s", subscription.Name, subscription.Id),
+		TenantId: subscription.TenantId,
+		Scopes:   []string{subscription.ResourceId},
 	}
 
-	resources, err := fetchResources(subscription, ctx)
+	resources, err := fetchResources(subscription, azCtx)
 
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	postProcess(resources)
 
 	addDependencyToSubscriptions(resources, subscription)
 
-	resources = normalize(resources, ctx.TenantId, set.New[string]())
+	resources = normalize(resources, azCtx.TenantId, set.New[string]())
 
 	// input resources can contain references to resources that do not exist (in other subscriptions for example). These need to be removed
 	resources = filterUnknownDependencies(resources)
 
-	return mapToProviderModel(resources), filename, nil
+	return mapToProviderModel(resources), metadata, nil
+}
+
+func (h *azureProvider) credentials() (azcore.TokenCredential, error) {
+	if h.options.Credentials != nil {
+		return h.options.Credentials, nil
+	}
+
+	return azidentity.NewDefaultAzureCredential(nil)
 }
 
 func mapToProviderModel(resources []*models.Resource) []*providers.Resource {
@@ -204,9 +236,11 @@ func fetchResources(subscription *azContext.SubscriptionContext, ctx *azContext.
 			handler := handlers[resource.Type]
 
 			return handler.GetResource(&azContext.Context{
+				Context:           ctx.Context,
 				SubscriptionId:    ctx.SubscriptionId,
 				TenantId:          ctx.TenantId,
 				Credentials:       ctx.Credentials,
+				ClientOptions:     ctx.ClientOptions,
 				ResourceGroupName: resource.ResourceGroup,
 				ResourceName:      resource.Name,
 				ResourceId:        resource.Id,
@@ -220,6 +254,9 @@ func fetchResources(subscription *azContext.SubscriptionContext, ctx *azContext.
 		return nil, err
 	}
 
+	// handlers create their resources from scratch, carry over the tags from the resource listing
+	addTags(resources, resourcesWithHandlers)
+
 	// add the resources that don't have any handlers as-is
 	resources = append(resources, resourcesWithoutHandlers...)
 
@@ -233,6 +270,34 @@ func fetchResources(subscription *azContext.SubscriptionContext, ctx *azContext.
 	return resources, nil
 }
 
+func addTags(resources, listedResources []*models.Resource) {
+	tags := map[string][]string{}
+
+	for _, listedResource := range listedResources {
+		t, ok := listedResource.Properties["tags"]
+
+		if !ok {
+			continue
+		}
+
+		tags[strings.ToLower(listedResource.Id)] = t
+	}
+
+	for _, resource := range resources {
+		t, ok := tags[strings.ToLower(resource.Id)]
+
+		if !ok {
+			continue
+		}
+
+		if resource.Properties == nil {
+			resource.Properties = map[string][]string{}
+		}
+
+		resource.Properties["tags"] = t
+	}
+}
+
 func postProcess(resources []*models.Resource) {
 	for _, resource := range resources {
 		handler, ok := handlers[resource.Type]
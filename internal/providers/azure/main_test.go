@@ -0,0 +1,144 @@
+package azure
+
+import (
+	"cloudsketch/internal/providers"
+	"cloudsketch/internal/providers/azure/recording"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	SUBSCRIPTION_ID = "00000000-0000-0000-0000-000000000001"
+	CASSETTE        = "testdata/subscription.cassette.json"
+	GOLDEN          = "testdata/subscription.golden.json"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the current output")
+
+func fetchFromCassette(t *testing.T, cassette *recording.Cassette, recorder *recording.Recorder) []*providers.Resource {
+	t.Helper()
+
+	server := recording.NewServer(cassette)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	clientOptions := recording.ClientOptions(httpServer.URL)
+
+	if recorder != nil {
+		clientOptions.Transport = recorder
+	}
+
+	provider := NewProvider(&Options{
+		Credentials:   recording.Credential{},
+		ClientOptions: clientOptions,
+	})
+
+	resources, _, err := provider.FetchResources(context.Background(), SUBSCRIPTION_ID)
+
+	if misses := server.Misses(); len(misses) > 0 {
+		t.Fatalf("requests without recorded response:\n%s", strings.Join(misses, "\n"))
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// resources are fetched concurrently
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Id < resources[j].Id
+	})
+
+	return resources
+}
+
+func TestFetchResourcesFromCassette(t *testing.T) {
+	cassette, err := recording.Load(CASSETTE)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources := fetchFromCassette(t, cassette, nil)
+
+	actual, err := json.MarshalIndent(resources, "", "\t")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := os.WriteFile(GOLDEN, actual, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(GOLDEN)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.TrimSpace(string(expected)) != strings.TrimSpace(string(actual)) {
+		t.Errorf("resources differ from %s. Run go test ./internal/providers/azure -update if the change is intended", GOLDEN)
+	}
+}
+
+// a cassette recorded with --record replays to the same resources
+func TestRecordedCassetteReplays(t *testing.T) {
+	cassette, err := recording.Load(CASSETTE)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := recording.NewRecorder(nil, []byte("key"))
+	expected := fetchFromCassette(t, cassette, recorder)
+
+	actual := fetchFromCassette(t, recorder.Cassette(), nil)
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("replaying the recorded cassette gave different resources")
+	}
+}
+
+// every handler must be exercised by the cassette. Record a subscription containing the new type with --record
+func TestCassetteCoversEveryHandler(t *testing.T) {
+	cassette, err := recording.Load(CASSETTE)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listed := map[string]bool{}
+
+	for _, interaction := range cassette.Interactions {
+		var page struct {
+			Value []struct {
+				Type string
+			}
+		}
+
+		if !strings.Contains(interaction.Path, "/resources?") || json.Unmarshal(interaction.Body, &page) != nil {
+			continue
+		}
+
+		for _, resource := range page.Value {
+			listed[resource.Type] = true
+		}
+	}
+
+	for typ := range handlers {
+		if !listed[typ] {
+			t.Errorf("no resource of type %s in %s", typ, CASSETTE)
+		}
+	}
+}
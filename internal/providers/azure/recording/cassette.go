@@ -0,0 +1,42 @@
+package recording
+
+import (
+	"cloudsketch/internal/marshall"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Interaction is a single request to Azure Resource Manager and the response it got
+type Interaction struct {
+	Method string
+	// path and query of the request, without the endpoint
+	Path       string
+	StatusCode int
+	Body       json.RawMessage
+}
+
+// Cassette holds the recorded interactions of a run, ordered by request
+type Cassette struct {
+	Interactions []*Interaction
+}
+
+func Load(path string) (*Cassette, error) {
+	return marshall.UnmarshallResources[Cassette](path)
+}
+
+func (c *Cassette) Save(path string) error {
+	return marshall.MarshallResources(path, c)
+}
+
+// key identifies a request. ARM paths are case insensitive and the order of query parameters does not matter
+func key(method, path string) string {
+	u, err := url.Parse(path)
+
+	if err != nil {
+		return strings.ToLower(fmt.Sprintf("%s %s", method, path))
+	}
+
+	return strings.ToLower(fmt.Sprintf("%s %s?%s", method, u.Path, u.Query().Encode()))
+}
@@ -0,0 +1,98 @@
+package recording
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// Recorder is a transport for the ARM clients that passes requests on and keeps a sanitized copy of every response
+type Recorder struct {
+	transport    policy.Transporter
+	sanitizer    *sanitizer
+	mutex        sync.Mutex
+	interactions map[string]*Interaction
+}
+
+// NewRecorder wraps a transport. nil uses the default HTTP client. Ids are replaced with pseudonyms derived with the key,
+// recordings made with the same key use the same pseudonyms
+func NewRecorder(transport policy.Transporter, key []byte) *Recorder {
+	if transport == nil {
+		transport = http.DefaultClient
+	}
+
+	return &Recorder{
+		transport:    transport,
+		sanitizer:    newSanitizer(key),
+		interactions: map[string]*Interaction{},
+	}
+}
+
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the client still has to read the response
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sanitizedBody, err := r.sanitizer.body(fmt.Sprintf("%s://%s", req.URL.Scheme, req.URL.Host), body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &Interaction{
+		Method:     req.Method,
+		Path:       r.sanitizer.text(req.URL.RequestURI()),
+		StatusCode: resp.StatusCode,
+		Body:       sanitizedBody,
+	}
+
+	// retried requests overwrite earlier attempts
+	r.interactions[key(interaction.Method, interaction.Path)] = interaction
+
+	return resp, nil
+}
+
+// Cassette returns the interactions recorded so far
+func (r *Recorder) Cassette() *Cassette {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys := []string{}
+
+	for k := range r.interactions {
+		keys = append(keys, k)
+	}
+
+	// requests are sent concurrently, sorting keeps re-recorded cassettes comparable
+	sort.Strings(keys)
+
+	cassette := &Cassette{
+		Interactions: []*Interaction{},
+	}
+
+	for _, k := range keys {
+		cassette.Interactions = append(cassette.Interactions, r.interactions[k])
+	}
+
+	return cassette
+}
@@ -0,0 +1,154 @@
+package recording
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeTransport struct {
+	body string
+}
+
+func (f *fakeTransport) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestRecorderSanitizesResponses(t *testing.T) {
+	transport := &fakeTransport{
+		body: `{
+			"value": [{"id": "/subscriptions/3F2504E0-4F89-11D3-9A0C-0305E82C3301/resourceGroups/rg", "tenantId": "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"}],
+			"properties": {"azureStorageAccounts": {"app": {"accountName": "storage", "accessKey": "c2VjcmV0"}}},
+			"nextLink": "https://management.azure.com/subscriptions/3f2504e0-4f89-11d3-9a0c-0305e82c3301/resourcegroups?$skiptoken=1"
+		}`,
+	}
+
+	recorder := NewRecorder(transport, []byte("key"))
+
+	req, _ := http.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions/3f2504e0-4f89-11d3-9a0c-0305e82c3301/resourcegroups?api-version=2021-04-01", nil)
+
+	resp, err := recorder.Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the caller still gets the original response
+	original, _ := io.ReadAll(resp.Body)
+
+	if !strings.Contains(string(original), "c2VjcmV0") {
+		t.Errorf("response passed on to the client was modified: %s", original)
+	}
+
+	interactions := recorder.Cassette().Interactions
+
+	if len(interactions) != 1 {
+		t.Fatalf("expected 1 interaction, got %v", len(interactions))
+	}
+
+	interaction := interactions[0]
+	body := string(interaction.Body)
+
+	sanitizer := newSanitizer([]byte("key"))
+	subscription := sanitizer.text("3f2504e0-4f89-11d3-9a0c-0305e82c3301")
+	tenant := sanitizer.text("9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d")
+
+	if !strings.HasPrefix(subscription, PLACEHOLDER_PREFIX) || subscription == tenant {
+		t.Errorf("unexpected pseudonyms %s and %s", subscription, tenant)
+	}
+
+	if interaction.Path != "/subscriptions/"+subscription+"/resourcegroups?api-version=2021-04-01" {
+		t.Errorf("unexpected path %s", interaction.Path)
+	}
+
+	for _, leaked := range []string{"3f2504e0", "3F2504E0", "9b1deb4d", "c2VjcmV0", "management.azure.com"} {
+		if strings.Contains(body, leaked) {
+			t.Errorf("recorded body contains %s: %s", leaked, body)
+		}
+	}
+
+	for _, expected := range []string{
+		`"/subscriptions/` + subscription + `/resourceGroups/rg"`,
+		`"tenantId":"` + tenant + `"`,
+		`"accessKey":"REDACTED"`,
+		`"accountName":"storage"`,
+		`"{endpoint}/subscriptions/` + subscription + `/resourcegroups?$skiptoken=1"`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("recorded body does not contain %s: %s", expected, body)
+		}
+	}
+}
+
+func TestPseudonymsDoNotDependOnOrder(t *testing.T) {
+	guids := []string{"3f2504e0-4f89-11d3-9a0c-0305e82c3301", "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"}
+
+	first, second := newSanitizer([]byte("key")), newSanitizer([]byte("key"))
+
+	a, b := first.text(guids[0]), first.text(guids[1])
+
+	if second.text(guids[1]) != b || second.text(strings.ToUpper(guids[0])) != a {
+		t.Error("the same key gave different pseudonyms when the ids were seen in another order")
+	}
+
+	if newSanitizer([]byte("other")).text(guids[0]) == a {
+		t.Error("another key gave the same pseudonym")
+	}
+}
+
+func TestServerReplaysCassette(t *testing.T) {
+	cassette := &Cassette{
+		Interactions: []*Interaction{
+			{
+				Method:     http.MethodGet,
+				Path:       "/subscriptions/00000000-0000-0000-0000-000000000001/resourcegroups?api-version=2021-04-01&$top=10",
+				StatusCode: http.StatusOK,
+				Body:       []byte(`{"nextLink":"{endpoint}/next"}`),
+			},
+		},
+	}
+
+	server := NewServer(cassette)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	// paths are case insensitive and query parameters can come in any order
+	resp, err := http.Get(httpServer.URL + "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups?$top=10&api-version=2021-04-01")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %v", resp.StatusCode)
+	}
+
+	if string(body) != `{"nextLink":"`+httpServer.URL+`/next"}` {
+		t.Errorf("endpoint was not replaced: %s", body)
+	}
+
+	resp, err = http.Get(httpServer.URL + "/subscriptions/00000000-0000-0000-0000-000000000001/providers")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for a request that was not recorded, got %v", resp.StatusCode)
+	}
+
+	if len(server.Misses()) != 1 {
+		t.Errorf("expected 1 miss, got %v", server.Misses())
+	}
+}
@@ -0,0 +1,102 @@
+package recording
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+const (
+	// replaces the ARM endpoint in recorded bodies, for example in the nextLink of paged responses
+	ENDPOINT = "{endpoint}"
+	REDACTED = "REDACTED"
+	// the zeros mark pseudonyms as placeholders
+	PLACEHOLDER_PREFIX = "00000000-0000-0000-0000-"
+)
+
+var (
+	// values of properties whose name contains one of these are never recorded
+	SECRET_PROPERTIES = []string{"password", "secret", "accesskey", "primarykey", "secondarykey", "connectionstring", "token"}
+
+	guidPattern = regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+)
+
+// sanitizer replaces subscription, tenant and other ids with pseudonyms and redacts secrets.
+// Pseudonyms are derived from the id with a keyed hash. The same id always gets the same pseudonym, regardless of the order
+// responses arrive in, so references between responses stay intact and recording again with the same key gives the same cassette
+type sanitizer struct {
+	key []byte
+}
+
+func newSanitizer(key []byte) *sanitizer {
+	return &sanitizer{
+		key: key,
+	}
+}
+
+func (s *sanitizer) text(t string) string {
+	return guidPattern.ReplaceAllStringFunc(t, func(guid string) string {
+		// recording a replayed cassette keeps its placeholders
+		if strings.HasPrefix(guid, PLACEHOLDER_PREFIX) {
+			return guid
+		}
+
+		mac := hmac.New(sha256.New, s.key)
+		mac.Write([]byte(strings.ToLower(guid)))
+
+		return PLACEHOLDER_PREFIX + hex.EncodeToString(mac.Sum(nil))[:12]
+	})
+}
+
+func (s *sanitizer) body(endpoint string, body []byte) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+
+	text := s.text(strings.ReplaceAll(string(body), endpoint, ENDPOINT))
+
+	var value any
+
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		// not every error response is JSON. Keep it as a string
+		return json.Marshal(text)
+	}
+
+	return json.Marshal(redact(value))
+}
+
+func redact(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for name, property := range v {
+			if _, ok := property.(string); ok && isSecret(name) {
+				v[name] = REDACTED
+				continue
+			}
+
+			v[name] = redact(property)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redact(item)
+		}
+	}
+
+	return value
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, secret := range SECRET_PROPERTIES {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+
+	return false
+}
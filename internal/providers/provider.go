@@ -1,5 +1,13 @@
 package providers
 
+import "context"
+
 type Provider interface {
-	FetchResources(subscriptionId string) ([]*Resource, string, error)
+	FetchResources(ctx context.Context, subscriptionId string) ([]*Resource, *Metadata, error)
+}
+
+type Metadata struct {
+	// Name is used as the base name of cached snapshots and diagrams
+	Name, TenantId string
+	Scopes         []string
 }
@@ -0,0 +1,129 @@
+package query
+
+import (
+	"cloudsketch/internal/datastructures/build_graph"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/list"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type Result struct {
+	Resource *models.Resource
+	// number of dependencies between the queried resource and this resource
+	Distance int
+}
+
+type query struct {
+	resources map[string]*models.Resource
+	graph     *build_graph.Build_graph
+}
+
+func New(resources []*models.Resource) (*query, error) {
+	resourceMap := map[string]*models.Resource{}
+
+	for _, resource := range resources {
+		resourceMap[resource.Id] = resource
+	}
+
+	// dependencies removed to break a cycle still matter when something goes down. The graph is only searched, so cycles are no problem
+	tasks := list.Map(resources, func(r *models.Resource) *build_graph.Task {
+		dependencies := slices.Concat(r.DependsOn, r.CyclicDependsOn)
+
+		return build_graph.NewTask(r.Id, list.Map(dependencies, func(d *models.Resource) string { return d.Id }), []string{}, []string{}, func() {})
+	})
+
+	graph, err := build_graph.NewCyclicGraph(tasks)
+
+	if err != nil {
+		return nil, fmt.Errorf("error during construction of dependency graph: %+v", err)
+	}
+
+	return &query{
+		resources: resourceMap,
+		graph:     graph,
+	}, nil
+}
+
+// Find looks up a resource by id or by name. Names must be unique
+func (q *query) Find(idOrName string) (*models.Resource, error) {
+	if resource, ok := q.resources[strings.ToLower(idOrName)]; ok {
+		return resource, nil
+	}
+
+	matches := []*models.Resource{}
+
+	for _, resource := range q.resources {
+		if strings.EqualFold(resource.Name, idOrName) {
+			matches = append(matches, resource)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no resource with id or name %s", idOrName)
+	case 1:
+		return matches[0], nil
+	}
+
+	ids := list.Map(matches, func(r *models.Resource) string { return r.Id })
+	sort.Strings(ids)
+
+	return nil, fmt.Errorf("%s matches multiple resources, use the id instead:\n%s", idOrName, strings.Join(ids, "\n"))
+}
+
+// Dependents returns every resource that stops working if the resource goes down
+func (q *query) Dependents(resource *models.Resource) []*Result {
+	return q.results(q.graph.Dependents(resource.Id))
+}
+
+// Dependencies returns every resource the resource needs to work
+func (q *query) Dependencies(resource *models.Resource) []*Result {
+	return q.results(q.graph.Dependencies(resource.Id))
+}
+
+// Path returns the shortest chain of dependencies between two resources, in whichever direction it exists
+func (q *query) Path(from, to *models.Resource) ([]*models.Resource, bool) {
+	path, ok := q.graph.ShortestPath(from.Id, to.Id)
+
+	if !ok {
+		path, ok = q.graph.ShortestPath(to.Id, from.Id)
+
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return list.Map(path, func(t *build_graph.Task) *models.Resource {
+		return q.resources[t.Label]
+	}), true
+}
+
+func (q *query) results(levels [][]*build_graph.Task) []*Result {
+	results := []*Result{}
+
+	for i, level := range levels {
+		resources := list.Map(level, func(t *build_graph.Task) *models.Resource {
+			return q.resources[t.Label]
+		})
+
+		sort.Slice(resources, func(i, j int) bool {
+			if resources[i].Type != resources[j].Type {
+				return resources[i].Type < resources[j].Type
+			}
+
+			return resources[i].Name < resources[j].Name
+		})
+
+		for _, resource := range resources {
+			results = append(results, &Result{
+				Resource: resource,
+				Distance: i + 1,
+			})
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,111 @@
+package query
+
+import (
+	"cloudsketch/internal/list"
+	"cloudsketch/pkg/sketch"
+	"slices"
+	"testing"
+)
+
+const (
+	CYCLES = "../../pkg/sketch/testdata/fixtures/cycles.json"
+)
+
+// the cycles fixture contains cycle-a -> cycle-b -> cycle-kv -> cycle-a. Breaking it moves one of the dependencies to CyclicDependsOn
+func cycles(t *testing.T) *query {
+	s, err := sketch.Load(CYCLES)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources, err := sketch.Resources(s)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := New(resources)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return q
+}
+
+func find(t *testing.T, q *query, name string) *sketch.Resource {
+	resource, err := q.Find(name)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resource
+}
+
+func names(results []*Result) []string {
+	return list.Map(results, func(r *Result) string { return r.Resource.Name })
+}
+
+func TestDependentsFollowBrokenCycles(t *testing.T) {
+	q := cycles(t)
+
+	for name, expected := range map[string][]string{
+		"cycle-kv": {"cycle-b", "cycle-a", "cyclestorage"},
+		"cycle-a":  {"cycle-kv", "cyclestorage", "cycle-b"},
+		"cycle-b":  {"cycle-a", "cycle-kv", "cyclestorage"},
+	} {
+		actual := names(q.Dependents(find(t, q, name)))
+
+		slices.Sort(actual)
+		slices.Sort(expected)
+
+		if !slices.Equal(actual, expected) {
+			t.Errorf("dependents of %s: expected %v, got %v", name, expected, actual)
+		}
+	}
+}
+
+func TestDependenciesFollowBrokenCycles(t *testing.T) {
+	q := cycles(t)
+
+	actual := names(q.Dependencies(find(t, q, "cycle-b")))
+	expected := []string{"cycle-kv", "cycle-a", "fixture-subscription"}
+
+	slices.Sort(actual)
+	slices.Sort(expected)
+
+	if !slices.Equal(actual, expected) {
+		t.Errorf("dependencies of cycle-b: expected %v, got %v", expected, actual)
+	}
+}
+
+func TestDistancesInCycles(t *testing.T) {
+	q := cycles(t)
+
+	for _, result := range q.Dependents(find(t, q, "cycle-kv")) {
+		expected := map[string]int{"cycle-b": 1, "cycle-a": 2, "cyclestorage": 3}[result.Resource.Name]
+
+		if result.Distance != expected {
+			t.Errorf("%s: expected distance %v, got %v", result.Resource.Name, expected, result.Distance)
+		}
+	}
+}
+
+func TestPathThroughBrokenCycle(t *testing.T) {
+	q := cycles(t)
+
+	path, ok := q.Path(find(t, q, "cycle-b"), find(t, q, "cycle-a"))
+
+	if !ok {
+		t.Fatal("expected a path from cycle-b to cycle-a")
+	}
+
+	actual := list.Map(path, func(r *sketch.Resource) string { return r.Name })
+	expected := []string{"cycle-b", "cycle-kv", "cycle-a"}
+
+	if !slices.Equal(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,188 @@
+package snapshot
+
+import (
+	"bytes"
+	"cloudsketch/internal/list"
+	"cloudsketch/internal/marshall"
+	"cloudsketch/internal/providers"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// bump when the format of the snapshot or the meaning of resource properties changes, and add a migration
+	SCHEMA_VERSION = 1
+
+	// snapshots used to be a bare array of resources. Their resources are those of the first envelope
+	LEGACY_SCHEMA_VERSION = 1
+)
+
+type Header struct {
+	SchemaVersion int
+	Provider      string
+	TenantId      string
+	Scopes        []string
+	Name          string
+	FetchedAt     time.Time
+	Version       string
+}
+
+type Snapshot struct {
+	Header    *Header
+	Resources []*providers.Resource
+}
+
+// migrations upgrade a snapshot from the version they are registered under to the next version
+var migrations = map[int]func(*Snapshot) error{}
+
+func New(resources []*providers.Resource, metadata *providers.Metadata, provider, version string) *Snapshot {
+	return &Snapshot{
+		Header: &Header{
+			SchemaVersion: SCHEMA_VERSION,
+			Provider:      provider,
+			TenantId:      metadata.TenantId,
+			Scopes:        metadata.Scopes,
+			Name:          metadata.Name,
+			FetchedAt:     time.Now().UTC(),
+			Version:       version,
+		},
+		Resources: resources,
+	}
+}
+
+func (s *Snapshot) Age() time.Duration {
+	return time.Since(s.Header.FetchedAt)
+}
+
+func (s *Snapshot) IsExpired(ttl time.Duration) bool {
+	// a ttl of zero means snapshots never expire
+	return ttl > 0 && s.Age() > ttl
+}
+
+// Read reads a snapshot, including bare arrays of resources
+func Read(path string) (*Snapshot, error) {
+	return read(path, true)
+}
+
+// ReadEnvelope only reads snapshots with a header, as written by cloudsketch
+func ReadEnvelope(path string) (*Snapshot, error) {
+	return read(path, false)
+}
+
+func read(path string, allowLegacy bool) (*Snapshot, error) {
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content = bytes.TrimSpace(content)
+
+	var snapshot *Snapshot
+
+	isLegacy := len(content) > 0 && content[0] == '['
+
+	if isLegacy && !allowLegacy {
+		return nil, fmt.Errorf("%s is a bare array of resources, not a snapshot with a header", path)
+	}
+
+	if isLegacy {
+		snapshot, err = readLegacy(path, content)
+	} else {
+		snapshot, err = readEnvelope(path, content)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(path, snapshot, SCHEMA_VERSION); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func readEnvelope(path string, content []byte) (*Snapshot, error) {
+	var snapshot *Snapshot
+
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, err
+	}
+
+	if snapshot == nil || snapshot.Header == nil {
+		return nil, fmt.Errorf("%s is not a snapshot", path)
+	}
+
+	// every envelope cloudsketch wrote carries a schema version
+	if snapshot.Header.SchemaVersion < 1 {
+		return nil, fmt.Errorf("%s has no schema version", path)
+	}
+
+	return snapshot, nil
+}
+
+func readLegacy(path string, content []byte) (*Snapshot, error) {
+	var resources []*providers.Resource
+
+	if err := json.Unmarshal(content, &resources); err != nil {
+		return nil, err
+	}
+
+	valid := len(resources) > 0 && list.All(resources, func(r *providers.Resource) bool {
+		return r != nil && r.Id != "" && r.Type != ""
+	})
+
+	if !valid {
+		return nil, fmt.Errorf("%s is not a snapshot", path)
+	}
+
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{
+		Header: &Header{
+			SchemaVersion: LEGACY_SCHEMA_VERSION,
+			Name:          strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+			FetchedAt:     info.ModTime(),
+		},
+		Resources: resources,
+	}, nil
+}
+
+func migrate(path string, snapshot *Snapshot, target int) error {
+	if snapshot.Header.SchemaVersion > target {
+		return fmt.Errorf("%s has schema version %v but this version of cloudsketch only understands up to %v. Upgrade cloudsketch", path, snapshot.Header.SchemaVersion, target)
+	}
+
+	for snapshot.Header.SchemaVersion < target {
+		migration, ok := migrations[snapshot.Header.SchemaVersion]
+
+		if !ok {
+			return fmt.Errorf("no migration from schema version %v", snapshot.Header.SchemaVersion)
+		}
+
+		if err := migration(snapshot); err != nil {
+			return fmt.Errorf("migration from schema version %v failed: %+v", snapshot.Header.SchemaVersion, err)
+		}
+
+		snapshot.Header.SchemaVersion++
+	}
+
+	return nil
+}
+
+func Write(path string, snapshot *Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	return marshall.MarshallResources(path, snapshot)
+}
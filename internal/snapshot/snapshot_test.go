@@ -0,0 +1,136 @@
+package snapshot
+
+import (
+	"cloudsketch/internal/providers"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func resources() []*providers.Resource {
+	return []*providers.Resource{
+		{Id: "/subscriptions/1", Type: "SUBSCRIPTION", Name: "subscription"},
+		{Id: "/subscriptions/1/resourcegroups/rg/providers/microsoft.keyvault/vaults/kv", Type: "KEY_VAULT", Name: "kv", DependsOn: []string{"/subscriptions/1"}},
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestReadEnvelope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subscription.json")
+	expected := New(resources(), &providers.Metadata{Name: "subscription", TenantId: "tenant", Scopes: []string{"/subscriptions/1"}}, "azure", "test")
+
+	if err := Write(path, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := Read(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !actual.Header.FetchedAt.Equal(expected.Header.FetchedAt) {
+		t.Errorf("expected fetched at %v, got %v", expected.Header.FetchedAt, actual.Header.FetchedAt)
+	}
+
+	actual.Header.FetchedAt = expected.Header.FetchedAt
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected.Header, actual.Header)
+	}
+}
+
+func TestReadLegacy(t *testing.T) {
+	path := writeFile(t, "example.json", `[
+		{"Id": "/subscriptions/1", "Type": "SUBSCRIPTION", "Name": "subscription"},
+		{"Id": "/subscriptions/1/resourcegroups/rg/providers/microsoft.keyvault/vaults/kv", "Type": "KEY_VAULT", "Name": "kv", "DependsOn": ["/subscriptions/1"]}
+	]`)
+
+	s, err := Read(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Header.SchemaVersion != SCHEMA_VERSION {
+		t.Errorf("expected schema version %v, got %v", SCHEMA_VERSION, s.Header.SchemaVersion)
+	}
+
+	if s.Header.Name != "example" {
+		t.Errorf("expected the file name as name, got %s", s.Header.Name)
+	}
+
+	if !reflect.DeepEqual(s.Resources, resources()) {
+		t.Errorf("expected %+v, got %+v", resources(), s.Resources)
+	}
+}
+
+func TestRejects(t *testing.T) {
+	for name, tc := range map[string]struct {
+		content string
+		err     string
+	}{
+		"empty array":           {`[]`, "is not a snapshot"},
+		"array of other things": {`[{"name": "not a resource"}]`, "is not a snapshot"},
+		"object without header": {`{"Resources": []}`, "is not a snapshot"},
+		"missing version":       {`{"Header": {"Provider": "azure"}, "Resources": []}`, "has no schema version"},
+		"zero version":          {`{"Header": {"SchemaVersion": 0}, "Resources": []}`, "has no schema version"},
+		"newer version":         {`{"Header": {"SchemaVersion": 1000}, "Resources": []}`, "Upgrade cloudsketch"},
+	} {
+		_, err := Read(writeFile(t, "snapshot.json", tc.content))
+
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected an error containing %q, got %v", name, tc.err, err)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	migrations[1] = func(s *Snapshot) error {
+		s.Resources = s.Resources[1:]
+		return nil
+	}
+
+	defer delete(migrations, 1)
+
+	s := &Snapshot{Header: &Header{SchemaVersion: 1}, Resources: resources()}
+
+	if err := migrate("snapshot.json", s, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Header.SchemaVersion != 2 {
+		t.Errorf("expected schema version 2, got %v", s.Header.SchemaVersion)
+	}
+
+	if len(s.Resources) != 1 {
+		t.Errorf("expected the migration to run once, got %v resources", len(s.Resources))
+	}
+}
+
+func TestMigrateWithoutMigration(t *testing.T) {
+	s := &Snapshot{Header: &Header{SchemaVersion: 1}, Resources: resources()}
+
+	if err := migrate("snapshot.json", s, 2); err == nil {
+		t.Error("expected an error for a missing migration")
+	}
+}
+
+func TestReadEnvelopeRejectsLegacy(t *testing.T) {
+	path := writeFile(t, "example.json", `[{"Id": "/subscriptions/1", "Type": "SUBSCRIPTION", "Name": "subscription"}]`)
+
+	if _, err := ReadEnvelope(path); err == nil {
+		t.Error("expected a bare array of resources to be rejected")
+	}
+}
@@ -0,0 +1,437 @@
+package sketch
+
+import (
+	"bytes"
+	"cloudsketch/internal/filter"
+	"cloudsketch/internal/list"
+	"cloudsketch/internal/query"
+	"context"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	FIXTURES = "testdata/fixtures"
+	GOLDEN   = "testdata/golden"
+	EXAMPLE  = "../../example/example.json"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the current output")
+
+// TestGolden renders every fixture with every frontend and compares the result with the golden files. Run with -update after an intended change
+func TestGolden(t *testing.T) {
+	for name, path := range fixtures(t) {
+		t.Run(name, func(t *testing.T) {
+			s, err := Load(path)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, frontend := range Frontends() {
+				t.Run(frontend, func(t *testing.T) {
+					// renders share no state, every frontend gets its own resources
+					t.Parallel()
+
+					resources, err := Resources(s)
+
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					var buffer bytes.Buffer
+
+					if err := Render(context.Background(), &buffer, resources, &RenderOptions{Frontend: frontend, Name: name}); err != nil {
+						t.Fatal(err)
+					}
+
+					compareWithGolden(t, filepath.Join(GOLDEN, fmt.Sprintf("%s.%s", name, Extension(frontend))), normalize(buffer.String()))
+				})
+			}
+		})
+	}
+}
+
+// TestGoldenPages renders the multi-page drawio diagrams of a few fixtures and the example
+func TestGoldenPages(t *testing.T) {
+	paths := fixtures(t)
+
+	for _, name := range []string{"app_services", "example", "networking"} {
+		s, err := Load(paths[name])
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resources, err := Resources(s)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, pages := range Pages() {
+			t.Run(fmt.Sprintf("%s/%s", name, pages), func(t *testing.T) {
+				var buffer bytes.Buffer
+
+				if err := Render(context.Background(), &buffer, resources, &RenderOptions{Name: name, Pages: pages}); err != nil {
+					t.Fatal(err)
+				}
+
+				compareWithGolden(t, filepath.Join(GOLDEN, fmt.Sprintf("%s.pages-%s.drawio", name, pages)), normalize(buffer.String()))
+			})
+		}
+	}
+}
+
+// TestGoldenLayers renders the drawio diagrams with a layer per category. Application gateways are moved to the compute layer
+func TestGoldenLayers(t *testing.T) {
+	paths := fixtures(t)
+
+	for _, name := range []string{"app_services", "example", "networking"} {
+		t.Run(name, func(t *testing.T) {
+			s, err := Load(paths[name])
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resources, err := Resources(s)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buffer bytes.Buffer
+
+			options := &RenderOptions{
+				Name:       name,
+				Layers:     true,
+				Categories: map[string]string{"APPLICATION_GATEWAY": "compute"},
+			}
+
+			if err := Render(context.Background(), &buffer, resources, options); err != nil {
+				t.Fatal(err)
+			}
+
+			compareWithGolden(t, filepath.Join(GOLDEN, fmt.Sprintf("%s.layers.drawio", name)), normalize(buffer.String()))
+		})
+	}
+}
+
+// TestRenderQuerySubgraph renders the dependencies of an app, as the query command does. The outbound subnet and the storage account of the app are not part of the subgraph
+func TestRenderQuerySubgraph(t *testing.T) {
+	s, err := Load(fixtures(t)["app_services"])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"app-func", "app-logic"} {
+		t.Run(name, func(t *testing.T) {
+			resources, err := Resources(s)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			q, err := query.New(resources)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resource, err := q.Find(name)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := append(list.Map(q.Dependencies(resource), func(r *query.Result) string { return r.Resource.Id }), resource.Id)
+			subgraph := filter.Keep(resources, ids)
+
+			for _, frontend := range Frontends() {
+				t.Run(frontend, func(t *testing.T) {
+					if err := Render(context.Background(), &bytes.Buffer{}, subgraph, &RenderOptions{Frontend: frontend}); err != nil {
+						t.Fatal(err)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestFilterAndRenderDoNotModifyResources(t *testing.T) {
+	s, err := Load(fixtures(t)["app_services"])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources, err := Resources(s)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := describe(resources)
+
+	filtered, err := Filter(resources, &FilterOptions{Exclude: []FilterExpression{{Type: "SUBNET"}}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, frontend := range Frontends() {
+		if err := Render(context.Background(), &bytes.Buffer{}, resources, &RenderOptions{Frontend: frontend}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := Render(context.Background(), &bytes.Buffer{}, filtered, &RenderOptions{Frontend: frontend}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if after := describe(resources); after != before {
+		t.Errorf("filtering and rendering modified the resources\nbefore: %s\nafter:  %s", before, after)
+	}
+}
+
+// TestRenderFilteredTypes includes and excludes every type of every fixture and renders the result with every frontend and drawio
+// layout. Handlers must cope with dependencies and properties that point at resources that were filtered out
+func TestRenderFilteredTypes(t *testing.T) {
+	for name, path := range fixtures(t) {
+		s, err := Load(path)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resources, err := Resources(s)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		types := map[string]bool{}
+
+		for _, resource := range resources {
+			types[resource.Type] = true
+		}
+
+		for typ := range types {
+			for filterName, options := range map[string]*FilterOptions{
+				"exclude":          {Exclude: []FilterExpression{{Type: typ}}},
+				"include":          {Include: []FilterExpression{{Type: typ}}},
+				"include-neighbor": {Include: []FilterExpression{{Type: typ}}, Hops: 1},
+			} {
+				t.Run(fmt.Sprintf("%s/%s/%s", name, filterName, typ), func(t *testing.T) {
+					t.Parallel()
+
+					filtered, err := Filter(resources, options)
+
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					findings, err := Lint(resources, nil)
+
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					renders := list.Map(Frontends(), func(frontend string) *RenderOptions {
+						return &RenderOptions{Frontend: frontend, Findings: findings}
+					})
+
+					for _, pages := range Pages() {
+						renders = append(renders, &RenderOptions{Frontend: "drawio", Pages: pages, Layers: true, Findings: findings})
+					}
+
+					for _, options := range renders {
+						if err := Render(context.Background(), &bytes.Buffer{}, filtered, options); err != nil {
+							t.Errorf("%s %s: %+v", options.Frontend, options.Pages, err)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestDrawioEscapesNames(t *testing.T) {
+	resources := []*Resource{
+		{
+			Id:         "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.keyvault/vaults/kv",
+			Type:       "KEY_VAULT",
+			Name:       `kv & "secrets" <prod>`,
+			Properties: map[string][]string{"link": {"https://portal.azure.com"}},
+		},
+	}
+
+	var buffer bytes.Buffer
+
+	if err := Render(context.Background(), &buffer, resources, &RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	output := buffer.String()
+	decoder := xml.NewDecoder(strings.NewReader(output))
+
+	for {
+		_, err := decoder.Token()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("diagram is not valid XML: %v", err)
+		}
+	}
+
+	if !strings.Contains(output, `label="%name%"`) {
+		t.Error("the name in the label was not replaced with a placeholder")
+	}
+}
+
+func TestRenderRejectsUnknownCategories(t *testing.T) {
+	for _, categories := range []map[string]string{
+		{"APPLICATION_GATEWAY": "unknown"},
+		// a misspelled type would silently keep its default category
+		{"APPLICATION_GATEWAYS": "compute"},
+	} {
+		options := &RenderOptions{
+			Layers:     true,
+			Categories: categories,
+		}
+
+		if err := Render(context.Background(), &bytes.Buffer{}, []*Resource{}, options); err == nil {
+			t.Errorf("expected an error for %v", categories)
+		}
+	}
+}
+
+func TestRenderIsDeterministic(t *testing.T) {
+	s, err := Load(EXAMPLE)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, frontend := range Frontends() {
+		outputs := []string{}
+
+		for range 3 {
+			resources, err := Resources(s)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buffer bytes.Buffer
+
+			if err := Render(context.Background(), &buffer, resources, &RenderOptions{Frontend: frontend}); err != nil {
+				t.Fatal(err)
+			}
+
+			outputs = append(outputs, buffer.String())
+		}
+
+		if outputs[0] != outputs[1] || outputs[1] != outputs[2] {
+			t.Errorf("%s: rendering the same snapshot twice gave different output", frontend)
+		}
+	}
+}
+
+// describe prints the resources with their dependencies and properties, to detect modifications
+func describe(resources []*Resource) string {
+	return strings.Join(list.Map(resources, func(r *Resource) string {
+		dependencies := list.Map(r.DependsOn, func(d *Resource) string { return d.Id })
+		cyclic := list.Map(r.CyclicDependsOn, func(d *Resource) string { return d.Id })
+
+		return fmt.Sprintf("%s %v %v %v", r.Id, dependencies, cyclic, r.Properties)
+	}), "\n")
+}
+
+// fixtures returns the snapshots to render by name. The example snapshot is rendered alongside the targeted fixtures
+func fixtures(t *testing.T) map[string]string {
+	paths, err := filepath.Glob(filepath.Join(FIXTURES, "*.json"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := map[string]string{
+		"example": EXAMPLE,
+	}
+
+	for _, path := range paths {
+		result[strings.TrimSuffix(filepath.Base(path), ".json")] = path
+	}
+
+	return result
+}
+
+func compareWithGolden(t *testing.T, path, actual string) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		t.Fatalf("%v. Run go test ./pkg/sketch -update to create it", err)
+	}
+
+	expected := normalize(string(content))
+
+	if actual == expected {
+		return
+	}
+
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	for i := range max(len(expectedLines), len(actualLines)) {
+		e, a := line(expectedLines, i), line(actualLines, i)
+
+		if e != a {
+			t.Fatalf("output differs from %s at line %v\nexpected: %s\nactual:   %s\nrun go test ./pkg/sketch -update if the change is intended", path, i+1, e, a)
+		}
+	}
+}
+
+// normalize removes differences that do not matter, like line endings and trailing whitespace
+func normalize(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n")) + "\n"
+}
+
+func line(lines []string, i int) string {
+	if i >= len(lines) {
+		return "<end of file>"
+	}
+
+	return lines[i]
+}
@@ -0,0 +1,85 @@
+package sketch
+
+import (
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/types"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+type RenderOptions struct {
+	// defaults to DEFAULT_FRONTEND
+	Frontend string
+	// name of the diagram, used by frontends that embed a name in their output. Defaults to diagram
+	Name string
+	// overlaid on the diagram by frontends that support it
+	Findings []*Finding
+	// base URL or directory of the icons referenced by the d2 frontend. Defaults to DEFAULT_ICON_BASE
+	IconBase string
+	// split the drawio diagram into an overview and a page per subscription, virtual network or resource group, see Pages. Empty draws a single page
+	Pages string
+	// put the resources of the drawio diagram on a layer per category
+	Layers bool
+	// category per resource type, overriding the default categories. See Categories
+	Categories map[string]string
+}
+
+// Render writes the diagram of the resources with the frontend of the options. The given resources are not modified
+func Render(ctx context.Context, w io.Writer, resources []*Resource, options *RenderOptions) error {
+	if options == nil {
+		options = &RenderOptions{}
+	}
+
+	frontendString := options.Frontend
+
+	if frontendString == "" {
+		frontendString = DEFAULT_FRONTEND
+	}
+
+	newFrontend, ok := frontendmap[frontendString]
+
+	if !ok {
+		return fmt.Errorf("unknown frontend %s", frontendString)
+	}
+
+	if options.Pages != "" && !slices.Contains(Pages(), options.Pages) {
+		return fmt.Errorf("unknown page layout %s", options.Pages)
+	}
+
+	for _, typ := range slices.Sorted(maps.Keys(options.Categories)) {
+		if _, ok := types.CATEGORIES[typ]; !ok {
+			return fmt.Errorf("unknown resource type %s in categories", typ)
+		}
+
+		if category := options.Categories[typ]; !slices.Contains(Categories(), category) {
+			return fmt.Errorf("unknown category %s for %s. Valid categories are %s", category, typ, strings.Join(Categories(), ","))
+		}
+	}
+
+	name := options.Name
+
+	if name == "" {
+		name = "diagram"
+	}
+
+	iconBase := options.IconBase
+
+	if iconBase == "" {
+		iconBase = DEFAULT_ICON_BASE
+	}
+
+	// frontends add dependencies and properties while drawing, they get copies of the resources
+	return newFrontend().WriteDiagram(ctx, w, models.Clone(resources), &frontends.Options{
+		Name:       name,
+		Findings:   options.Findings,
+		IconBase:   iconBase,
+		Pages:      options.Pages,
+		Layers:     options.Layers,
+		Categories: options.Categories,
+	})
+}
@@ -0,0 +1,101 @@
+package sketch
+
+import (
+	"cloudsketch/internal/datastructures/build_graph"
+	"cloudsketch/internal/filter"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/lint"
+	"cloudsketch/internal/list"
+	"cloudsketch/internal/providers"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Resources resolves the dependencies between the resources of a snapshot, ordered by id. Cycles are broken, the removed dependencies end up in CyclicDependsOn
+func Resources(s *Snapshot) ([]*Resource, error) {
+	resource_map := &map[string]*Resource{}
+
+	tasks := list.Map(s.Resources, func(r *providers.Resource) *build_graph.Task {
+		// copy the dependencies, breaking cycles must not modify the snapshot
+		task := build_graph.NewTask(r.Id, append([]string{}, r.DependsOn...), []string{}, []string{}, nil)
+		task.Action = func() { addDependenciesFromIds(r, task.References, resource_map) }
+
+		return task
+	})
+
+	backEdges := build_graph.BreakCycles(tasks)
+
+	for _, backEdge := range backEdges {
+		log.Printf("breaking cyclic dependency %s. The last dependency is drawn dashed", strings.Join(backEdge.Cycle, " -> "))
+	}
+
+	bg, err := build_graph.NewGraph(tasks)
+
+	if err != nil {
+		return nil, fmt.Errorf("error during construction of dependency graph: %+v", err)
+	}
+
+	for _, task := range tasks {
+		bg.Resolve(task)
+	}
+
+	for _, backEdge := range backEdges {
+		from := (*resource_map)[backEdge.From]
+		from.CyclicDependsOn = append(from.CyclicDependsOn, (*resource_map)[backEdge.To])
+	}
+
+	domainResources := []*Resource{}
+	for _, v := range *resource_map {
+		domainResources = append(domainResources, v)
+	}
+
+	// sorted so rendering the same snapshot twice yields the same output
+	sort.Slice(domainResources, func(i, j int) bool {
+		return domainResources[i].Id < domainResources[j].Id
+	})
+
+	return domainResources, nil
+}
+
+func addDependenciesFromIds(resource *providers.Resource, dependsOn []string, resource_map *map[string]*Resource) {
+	if (*resource_map)[resource.Id] != nil {
+		// resource already registered
+		return
+	}
+
+	dependencies := list.Map(dependsOn, func(d string) *Resource {
+		return (*resource_map)[d]
+	})
+
+	(*resource_map)[resource.Id] = &Resource{
+		Id:         resource.Id,
+		Type:       resource.Type,
+		Name:       resource.Name,
+		DependsOn:  dependencies,
+		Properties: resource.Properties,
+	}
+}
+
+// ParseFilterExpression parses expressions of the form <field>=<value>, where field is type, name, rg, tag or property
+func ParseFilterExpression(s string) (FilterExpression, error) {
+	return filter.ParseExpression(s)
+}
+
+// Filter returns the resources matching the options. The given resources are not modified, the result holds copies
+func Filter(resources []*Resource, options *FilterOptions) ([]*Resource, error) {
+	if options == nil {
+		return resources, nil
+	}
+
+	return filter.Apply(models.Clone(resources), options)
+}
+
+func Lint(resources []*Resource, options *LintOptions) ([]*Finding, error) {
+	if options == nil {
+		options = &LintOptions{}
+	}
+
+	return lint.Run(resources, options)
+}
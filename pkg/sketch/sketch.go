@@ -0,0 +1,121 @@
+// Package sketch exposes cloudsketch as a library: fetch or load a snapshot of a subscription, turn it into a
+// dependency graph, filter and lint it, and render it with any of the frontends.
+package sketch
+
+import (
+	"cloudsketch/internal/filter"
+	"cloudsketch/internal/frontends"
+	"cloudsketch/internal/frontends/d2"
+	"cloudsketch/internal/frontends/dot"
+	"cloudsketch/internal/frontends/drawio"
+	"cloudsketch/internal/frontends/excalidraw"
+	"cloudsketch/internal/frontends/graphml"
+	"cloudsketch/internal/frontends/inventory"
+	"cloudsketch/internal/frontends/ipam"
+	"cloudsketch/internal/frontends/jsongraph"
+	"cloudsketch/internal/frontends/models"
+	"cloudsketch/internal/frontends/plantuml"
+	"cloudsketch/internal/frontends/structurizr"
+	"cloudsketch/internal/frontends/types"
+	"cloudsketch/internal/lint"
+	lintModels "cloudsketch/internal/lint/models"
+	"cloudsketch/internal/providers"
+	"cloudsketch/internal/providers/azure"
+	"cloudsketch/internal/snapshot"
+	"sort"
+)
+
+const (
+	DEFAULT_PROVIDER = "azure"
+	DEFAULT_FRONTEND = "drawio"
+	// drawio serves the icons used by the drawio frontend
+	DEFAULT_ICON_BASE = "https://app.diagrams.net"
+)
+
+type (
+	// Snapshot holds the resources fetched from a provider, as they are cached on disk
+	Snapshot = snapshot.Snapshot
+	Header   = snapshot.Header
+
+	// Resource is a resource with its dependencies resolved, as rendered by the frontends
+	Resource = models.Resource
+
+	FilterOptions    = filter.Options
+	FilterExpression = filter.Expression
+
+	LintOptions = lint.Options
+	Suppression = lint.Suppression
+	Finding     = lintModels.Finding
+
+	// AzureOptions inject credentials, and the endpoint and transport of the ARM clients
+	AzureOptions = azure.Options
+)
+
+var (
+	providermap map[string]func(*FetchOptions) providers.Provider = map[string]func(*FetchOptions) providers.Provider{
+		"azure": func(options *FetchOptions) providers.Provider { return azure.NewProvider(options.Azure) },
+	}
+	frontendmap map[string]func() frontends.Frontend = map[string]func() frontends.Frontend{
+		"d2":            func() frontends.Frontend { return d2.New() },
+		"drawio":        func() frontends.Frontend { return drawio.New() },
+		"dot":           func() frontends.Frontend { return dot.New() },
+		"excalidraw":    func() frontends.Frontend { return excalidraw.New() },
+		"graphml":       func() frontends.Frontend { return graphml.New() },
+		"inventory":     func() frontends.Frontend { return inventory.New(inventory.FORMAT_MARKDOWN) },
+		"inventory-csv": func() frontends.Frontend { return inventory.New(inventory.FORMAT_CSV) },
+		"ipam":          func() frontends.Frontend { return ipam.New() },
+		"jsongraph":     func() frontends.Frontend { return jsongraph.New() },
+		"plantuml":      func() frontends.Frontend { return plantuml.New() },
+		"structurizr":   func() frontends.Frontend { return structurizr.New() },
+	}
+	// file extensions of frontends that are not named after their output format
+	extensionmap map[string]string = map[string]string{
+		// ipam already writes .md
+		"inventory":     "inventory.md",
+		"inventory-csv": "inventory.csv",
+		"ipam":          "md",
+		// .json would overwrite the cached snapshot
+		"jsongraph":   "graph.json",
+		"plantuml":    "puml",
+		"structurizr": "dsl",
+	}
+)
+
+func Providers() []string {
+	return keys(providermap)
+}
+
+func Frontends() []string {
+	return keys(frontendmap)
+}
+
+// Categories returns the categories resources can be put in
+func Categories() []string {
+	return types.Categories()
+}
+
+// Pages returns the ways the drawio diagram can be split into pages
+func Pages() []string {
+	return []string{frontends.PAGES_RESOURCE_GROUP, frontends.PAGES_SUBSCRIPTION, frontends.PAGES_VIRTUAL_NETWORK}
+}
+
+// Extension returns the file extension of the output of a frontend
+func Extension(frontend string) string {
+	if extension, ok := extensionmap[frontend]; ok {
+		return extension
+	}
+
+	return frontend
+}
+
+func keys[T any](m map[string]T) []string {
+	result := []string{}
+
+	for k := range m {
+		result = append(result, k)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,61 @@
+package sketch
+
+import (
+	"cloudsketch/internal/anonymize"
+	"cloudsketch/internal/snapshot"
+	"context"
+	"fmt"
+)
+
+type FetchOptions struct {
+	// defaults to DEFAULT_PROVIDER
+	Provider       string
+	SubscriptionId string
+	// recorded in the snapshot header
+	Version string
+	// only used by the azure provider. nil uses DefaultAzureCredential against the public cloud
+	Azure *AzureOptions
+}
+
+// Fetch queries the provider for every resource in the subscription
+func Fetch(ctx context.Context, options *FetchOptions) (*Snapshot, error) {
+	providerString := options.Provider
+
+	if providerString == "" {
+		providerString = DEFAULT_PROVIDER
+	}
+
+	newProvider, ok := providermap[providerString]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %s", providerString)
+	}
+
+	if options.SubscriptionId == "" {
+		return nil, fmt.Errorf("a subscription id is required")
+	}
+
+	resources, metadata, err := newProvider(options).FetchResources(ctx, options.SubscriptionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot.New(resources, metadata, providerString, options.Version), nil
+}
+
+// Load reads a snapshot, upgrading snapshots written by older versions
+func Load(path string) (*Snapshot, error) {
+	return snapshot.Read(path)
+}
+
+func Save(path string, s *Snapshot) error {
+	return snapshot.Write(path, s)
+}
+
+// Anonymize replaces ids, names and IP addresses with consistent pseudonyms. The mapping from pseudonym to original value is returned alongside
+func Anonymize(s *Snapshot) (*Snapshot, map[string]string) {
+	anonymizer := anonymize.New()
+
+	return anonymizer.Snapshot(s), anonymizer.Mapping()
+}